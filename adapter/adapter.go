@@ -0,0 +1,155 @@
+// Package adapter 让 *lru.Cache 满足其他缓存库的常见接口，便于接入接受可插拔缓存的框架
+// Ristretto 对应 ristretto v2 的泛型接口，Any 对应 ristretto v1 等以 interface{} 为键值的接口，
+// GroupCache 对应 groupcache/lru。适配器不持有额外状态，同一个缓存可以同时被多个适配器和原接口使用
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	lru "github.com/madokast/LRU"
+)
+
+// Ristretto ristretto 风格的适配器：Get/Set/SetWithTTL/Del/Clear/Wait
+// 缓存项的大小由 lru.New 的 sizeCal 决定，Set 的 cost 参数被忽略
+type Ristretto[K comparable, V interface{}] struct {
+	cache *lru.Cache[K, V]
+}
+
+// NewRistretto 包装 cache
+func NewRistretto[K comparable, V interface{}](cache *lru.Cache[K, V]) *Ristretto[K, V] {
+	return &Ristretto[K, V]{cache: cache}
+}
+
+func (r *Ristretto[K, V]) Get(key K) (V, bool) {
+	return r.cache.Get(key)
+}
+
+// Set 写入 key，cost 被忽略。SizeStrict 策略下大小不合法时返回 false
+func (r *Ristretto[K, V]) Set(key K, value V, cost int64) bool {
+	return r.cache.TryPut(key, value, 0) == nil
+}
+
+// SetWithTTL 写入 ttl 后过期的 key，ttl <= 0 时永不过期
+func (r *Ristretto[K, V]) SetWithTTL(key K, value V, cost int64, ttl time.Duration) bool {
+	return r.cache.TryPut(key, value, ttl) == nil
+}
+
+func (r *Ristretto[K, V]) Del(key K) {
+	r.cache.Remove(key)
+}
+
+func (r *Ristretto[K, V]) Clear() {
+	r.cache.RemoveAll()
+}
+
+// Wait 写入是同步的，无需等待
+func (r *Ristretto[K, V]) Wait() {}
+
+// ErrType Any 写入的 key 或 value 与缓存的类型不一致
+var ErrType = errors.New("adapter: type mismatch")
+
+// Any 以 interface{} 为键值的适配器，用于不支持泛型的框架
+// 类型与缓存不一致的 key 视为不存在，类型不一致的 value 不会写入
+type Any[K comparable, V interface{}] struct {
+	cache *lru.Cache[K, V]
+}
+
+// NewAny 包装 cache
+func NewAny[K comparable, V interface{}](cache *lru.Cache[K, V]) *Any[K, V] {
+	return &Any[K, V]{cache: cache}
+}
+
+func (a *Any[K, V]) Get(key interface{}) (interface{}, bool) {
+	k, ok := key.(K)
+	if !ok {
+		return nil, false
+	}
+	value, ok := a.cache.Get(k)
+	if !ok {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set 写入 key，cost 被忽略。key 或 value 的类型不一致、或写入被拒绝时返回 false
+func (a *Any[K, V]) Set(key, value interface{}, cost int64) bool {
+	return a.SetWithTTL(key, value, cost, 0)
+}
+
+// SetWithTTL 写入 ttl 后过期的 key，ttl <= 0 时永不过期。失败时返回 false，原因见 TrySetWithTTL
+func (a *Any[K, V]) SetWithTTL(key, value interface{}, cost int64, ttl time.Duration) bool {
+	return a.TrySetWithTTL(key, value, ttl) == nil
+}
+
+// TrySetWithTTL 同 SetWithTTL，返回写入失败的原因：类型不一致时返回 ErrType，写入被拒绝时返回 TryPut 的错误
+// value 为 nil 时只能写入指针、接口、切片等可以为 nil 的 V，其余类型返回 ErrType，不会写入零值
+func (a *Any[K, V]) TrySetWithTTL(key, value interface{}, ttl time.Duration) error {
+	k, ok := key.(K)
+	if !ok {
+		return fmt.Errorf("%w: key %T", ErrType, key)
+	}
+	v, ok := value.(V)
+	if !ok && (value != nil || !nillable[V]()) {
+		return fmt.Errorf("%w: value %T", ErrType, value)
+	}
+	return a.cache.TryPut(k, v, ttl)
+}
+
+// nillable V 的零值是否为 nil
+func nillable[V interface{}]() bool {
+	switch reflect.TypeOf((*V)(nil)).Elem().Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return true
+	}
+	return false
+}
+
+func (a *Any[K, V]) Del(key interface{}) {
+	if k, ok := key.(K); ok {
+		a.cache.Remove(k)
+	}
+}
+
+func (a *Any[K, V]) Clear() {
+	a.cache.RemoveAll()
+}
+
+// GroupCache groupcache/lru 风格的适配器：Add/Get/Remove/RemoveOldest/Len/Clear
+type GroupCache[K comparable, V interface{}] struct {
+	cache *lru.Cache[K, V]
+}
+
+// NewGroupCache 包装 cache
+func NewGroupCache[K comparable, V interface{}](cache *lru.Cache[K, V]) *GroupCache[K, V] {
+	return &GroupCache[K, V]{cache: cache}
+}
+
+func (g *GroupCache[K, V]) Add(key K, value V) {
+	g.cache.Put(key, value)
+}
+
+func (g *GroupCache[K, V]) Get(key K) (V, bool) {
+	return g.cache.Get(key)
+}
+
+func (g *GroupCache[K, V]) Remove(key K) {
+	g.cache.Remove(key)
+}
+
+// RemoveOldest 移除最久未访问的项，查找和移除之间有并发写入时移除的可能不是最久未访问的项
+func (g *GroupCache[K, V]) RemoveOldest() {
+	if e, ok := g.cache.LeastRecentlyUsed(); ok {
+		g.cache.Remove(e.Key())
+	}
+}
+
+func (g *GroupCache[K, V]) Len() int {
+	return g.cache.Number()
+}
+
+func (g *GroupCache[K, V]) Clear() {
+	g.cache.RemoveAll()
+}
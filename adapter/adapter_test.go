@@ -0,0 +1,92 @@
+package adapter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	lru "github.com/madokast/LRU"
+)
+
+func TestRistretto(t *testing.T) {
+	r := NewRistretto(lru.New[string, int](2, nil, nil))
+	if !r.Set("a", 1, 100) || !r.SetWithTTL("b", 2, 1, time.Hour) {
+		panic("set")
+	}
+	r.Wait()
+	if value, ok := r.Get("a"); !ok || value != 1 {
+		panic(value)
+	}
+	r.Del("a")
+	if _, ok := r.Get("a"); ok {
+		panic("a")
+	}
+	r.Clear()
+	if _, ok := r.Get("b"); ok {
+		panic("b")
+	}
+
+	strict := NewRistretto(lru.New[string, string](2, nil, func(key, value string) int { return len(value) },
+		lru.WithSizePolicy[string, string](lru.SizeStrict)))
+	if strict.Set("empty", "", 1) || !strict.Set("a", "x", 1) {
+		panic("strict")
+	}
+}
+
+func TestAny(t *testing.T) {
+	a := NewAny(lru.New[string, int](2, nil, nil))
+	if !a.Set("a", 1, 1) || a.Set(1, 1, 1) || a.Set("b", "x", 1) {
+		panic("set")
+	}
+	if value, ok := a.Get("a"); !ok || value != 1 {
+		panic(value)
+	}
+	if _, ok := a.Get(1); ok {
+		panic(1)
+	}
+	a.Del("a")
+	a.Del(1)
+	if _, ok := a.Get("a"); ok {
+		panic("a")
+	}
+
+	ptr := NewAny(lru.New[string, *int](2, nil, nil))
+	if !ptr.Set("nil", nil, 1) {
+		panic("nil")
+	}
+	if value, ok := ptr.Get("nil"); !ok || value.(*int) != nil {
+		panic(value)
+	}
+
+	// nil 不能写入不可为 nil 的类型，不写入零值
+	if err := a.TrySetWithTTL("zero", nil, 0); !errors.Is(err, ErrType) {
+		panic(err)
+	}
+	if err := a.TrySetWithTTL(1, 1, 0); !errors.Is(err, ErrType) {
+		panic(err)
+	}
+	if _, ok := a.Get("zero"); ok || a.Set("zero", nil, 1) {
+		panic("zero")
+	}
+}
+
+func TestGroupCache(t *testing.T) {
+	g := NewGroupCache(lru.New[string, int](3, nil, nil))
+	g.Add("a", 1)
+	g.Add("b", 2)
+	g.Add("c", 3)
+	g.Get("a")
+	g.RemoveOldest()
+	if _, ok := g.Get("b"); ok || g.Len() != 2 {
+		panic(g.Len())
+	}
+	g.Remove("a")
+	if g.Len() != 1 {
+		panic(g.Len())
+	}
+	g.Clear()
+	g.RemoveOldest()
+	if g.Len() != 0 {
+		panic(g.Len())
+	}
+}
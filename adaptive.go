@@ -0,0 +1,176 @@
+package lru
+
+import (
+	"container/list"
+	"fmt"
+	"time"
+)
+
+// AdaptiveCapacity 自适应容量配置
+type AdaptiveCapacity struct {
+	Min, Max       int           // maxSize 的调整范围
+	TargetHitRatio float64       // 目标命中率，取值 (0, 1]
+	MinGain        float64       // 扩容一步带来的命中率提升低于该值视为可以忽略，默认 0.01
+	Step           int           // 每次调整的大小，默认 (Max - Min) / 10
+	Interval       time.Duration // 调整周期，默认 1 分钟
+}
+
+// WithAdaptiveCapacity 在 [Min, Max] 内自动调整 maxSize 以维持目标命中率
+// 被淘汰项的 key 保存在大小为 Step 的幽灵列表中，未命中但命中幽灵列表说明缓存再大一步就能命中，
+// 由此估计扩容一步的命中率提升。每个周期结束时：命中率低于目标且提升不可忽略则扩容；
+// 命中率达到目标且提升可以忽略则缩容，但不会缩回到最近一次扩容前的大小，避免来回抖动，
+// 连续 adaptRetry 个周期满足缩容条件后才会再次尝试。调整是惰性的，在周期结束后的第一次 Get 或 Put 时进行。
+// 开启后 maxSize 的初始值会被限制在 [Min, Max] 内
+func WithAdaptiveCapacity[K comparable, V interface{}](config AdaptiveCapacity) Option[K, V] {
+	if config.MinGain <= 0 {
+		config.MinGain = 0.01
+	}
+	if config.Step <= 0 {
+		config.Step = (config.Max - config.Min) / 10
+		if config.Step <= 0 {
+			config.Step = 1
+		}
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	return func(o *options[K, V]) {
+		o.adaptive = &config
+	}
+}
+
+func (a *AdaptiveCapacity) validate() error {
+	if a.Min < 0 || a.Min > a.Max {
+		return fmt.Errorf("%w: adaptive capacity range [%d, %d]", ErrInvalidOption, a.Min, a.Max)
+	}
+	if a.TargetHitRatio <= 0 || a.TargetHitRatio > 1 {
+		return fmt.Errorf("%w: target hit ratio %v out of (0, 1]", ErrInvalidOption, a.TargetHitRatio)
+	}
+	return nil
+}
+
+// ghostList 只保存 key 和大小的 LRU 列表，用作被淘汰项的幽灵列表和影子缓存
+type ghostList[K comparable] struct {
+	li      *list.List // list<ghostEntry>
+	m       map[K]*list.Element
+	size    int
+	maxSize int
+}
+
+type ghostEntry[K comparable] struct {
+	key  K
+	size int
+}
+
+func newGhostList[K comparable](maxSize int) *ghostList[K] {
+	return &ghostList[K]{li: list.New(), m: map[K]*list.Element{}, maxSize: maxSize}
+}
+
+func (g *ghostList[K]) add(key K, size int) {
+	g.remove(key)
+	g.m[key] = g.li.PushFront(ghostEntry[K]{key: key, size: size})
+	g.size += size
+	for g.size > g.maxSize && g.li.Len() > 0 {
+		g.remove(g.li.Back().Value.(ghostEntry[K]).key)
+	}
+}
+
+// touch 命中时移到头部，返回 key 是否存在
+func (g *ghostList[K]) touch(key K) bool {
+	ele, ok := g.m[key]
+	if ok {
+		g.li.MoveToFront(ele)
+	}
+	return ok
+}
+
+// remove 移除 key，返回 key 是否存在
+func (g *ghostList[K]) remove(key K) bool {
+	ele, ok := g.m[key]
+	if !ok {
+		return false
+	}
+	delete(g.m, key)
+	g.li.Remove(ele)
+	g.size -= ele.Value.(ghostEntry[K]).size
+	return true
+}
+
+// adaptRetry 缩容被最近一次扩容阻止后，再次尝试缩回前需要稳定的周期数
+const adaptRetry = 10
+
+// adaptState 自适应容量当前周期的状态
+type adaptState[K comparable] struct {
+	floor     int // 最近一次扩容前的大小，缩容不低于 floor + Step
+	stable    int // 缩容被 floor 阻止的连续周期数
+	ghost     *ghostList[K]
+	start     time.Time
+	hits      uint64 // 周期开始时的命中数
+	misses    uint64 // 周期开始时的未命中数
+	ghostHits uint64 // 本周期幽灵列表命中数
+	total     uint64 // 累计幽灵列表命中数
+}
+
+// adaptUnlock 周期结束时调整 maxSize
+func (c *Cache[K, V]) adaptUnlock() {
+	if c.adaptive == nil {
+		return
+	}
+	now := time.Now()
+	if c.adapt.ghost == nil {
+		c.resetAdaptUnlock(now)
+		return
+	}
+	if now.Sub(c.adapt.start) < c.adaptive.Interval {
+		return
+	}
+	hits, misses := c.hits.Load()-c.adapt.hits, c.misses.Load()-c.adapt.misses
+	if requests := hits + misses; requests > 0 {
+		ratio := float64(hits) / float64(requests)
+		gain := float64(c.adapt.ghostHits) / float64(requests)
+		switch {
+		case ratio < c.adaptive.TargetHitRatio && gain >= c.adaptive.MinGain:
+			c.adapt.floor, c.adapt.stable = c.maxSize, 0
+			c.maxSize += c.adaptive.Step
+		case ratio >= c.adaptive.TargetHitRatio && gain < c.adaptive.MinGain:
+			if c.maxSize-c.adaptive.Step > c.adapt.floor {
+				c.maxSize -= c.adaptive.Step
+			} else if c.adapt.stable++; c.adapt.stable >= adaptRetry {
+				c.adapt.floor, c.adapt.stable = 0, 0
+			}
+		}
+	}
+	c.resetAdaptUnlock(now)
+	c.expireUnlock()
+}
+
+// resetAdaptUnlock 开始新的周期，并把 maxSize 限制在范围内
+func (c *Cache[K, V]) resetAdaptUnlock(now time.Time) {
+	if c.maxSize > c.adaptive.Max {
+		c.maxSize = c.adaptive.Max
+	}
+	if c.maxSize < c.adaptive.Min {
+		c.maxSize = c.adaptive.Min
+	}
+	if c.adapt.ghost == nil || c.adapt.ghost.maxSize != c.adaptive.Step {
+		c.adapt.ghost = newGhostList[K](c.adaptive.Step)
+	}
+	c.adapt.start = now
+	c.adapt.hits, c.adapt.misses = c.hits.Load(), c.misses.Load()
+	c.adapt.ghostHits = 0
+}
+
+// ghostMissUnlock 未命中时检查幽灵列表
+func (c *Cache[K, V]) ghostMissUnlock(key K) {
+	if c.adapt.ghost != nil && c.adapt.ghost.remove(key) {
+		c.adapt.ghostHits++
+		c.adapt.total++
+	}
+}
+
+// MaxSize 返回当前的最大缓存大小，开启 WithAdaptiveCapacity 时会随时间变化
+func (c *Cache[K, V]) MaxSize() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.maxSize
+}
@@ -0,0 +1,50 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_WithAdaptiveCapacity(t *testing.T) {
+	config := AdaptiveCapacity{Min: 2, Max: 20, TargetHitRatio: 0.9, Step: 2, Interval: 20 * time.Millisecond}
+	cache := New[int, int](100, nil, nil, WithAdaptiveCapacity[int, int](config))
+	access := func(key int) {
+		if _, ok := cache.Get(key); !ok {
+			cache.Put(key, key)
+		}
+	}
+
+	// 第一次访问把 maxSize 限制到 Max
+	access(0)
+	if cache.MaxSize() != 20 {
+		panic(cache.MaxSize())
+	}
+
+	// 只访问 2 个 key，全部命中，逐步缩容到 Min
+	for deadline := time.Now().Add(time.Second); cache.MaxSize() > 2 && time.Now().Before(deadline); {
+		access(0)
+		access(1)
+	}
+	if cache.MaxSize() != 2 {
+		panic(cache.MaxSize())
+	}
+
+	// 循环访问 4 个 key，容量 2 时全部未命中但都命中幽灵列表，扩容到 4 后不再缩回
+	for end := time.Now().Add(100 * time.Millisecond); time.Now().Before(end); {
+		for i := 0; i < 4; i++ {
+			access(i)
+		}
+	}
+	if cache.MaxSize() != 4 || cache.Stats().GhostHits == 0 {
+		panic(cache.MaxSize())
+	}
+}
+
+func TestCache_WithAdaptiveCapacityInvalid(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	err := cache.Configure(WithAdaptiveCapacity[int, int](AdaptiveCapacity{Min: 10, Max: 5, TargetHitRatio: 0.9}))
+	if !errors.Is(err, ErrInvalidOption) {
+		panic(err)
+	}
+}
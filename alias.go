@@ -0,0 +1,72 @@
+package lru
+
+// Alias 为已存在的 primary 注册别名，之后 Get、GetNoMove、Put、Remove 和 Do 使用 alias 时都作用于 primary 的缓存项
+// 例如 URL 缓存中同一对象的多个等价地址，无需重复保存 value。primary 不存在、alias 已是缓存中的 key、alias 与 primary 相同或 alias 未通过 WithKeyValidator 的校验时返回 false。
+// primary 被移除时它的别名一并删除。别名不写入快照和预写日志
+func (c *Cache[K, V]) Alias(alias, primary K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	primary = c.resolveUnlock(primary)
+	if alias == primary || c.checkKey(alias) != nil {
+		return false
+	}
+	if _, ok := c.m[primary]; !ok {
+		return false
+	}
+	if _, ok := c.m[alias]; ok {
+		return false
+	}
+	c.removeAliasUnlock(alias)
+	if c.aliases == nil {
+		c.aliases = map[K]K{}
+		c.aliasesOf = map[K]map[K]struct{}{}
+	}
+	c.aliases[alias] = primary
+	if c.aliasesOf[primary] == nil {
+		c.aliasesOf[primary] = map[K]struct{}{}
+	}
+	c.aliasesOf[primary][alias] = struct{}{}
+	return true
+}
+
+// RemoveAlias 删除别名，不影响 primary 的缓存项
+func (c *Cache[K, V]) RemoveAlias(alias K) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.removeAliasUnlock(alias)
+}
+
+// resolve 同 resolveUnlock，在读锁下查找
+func (c *Cache[K, V]) resolve(key K) K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.resolveUnlock(key)
+}
+
+// resolveUnlock 返回 key 对应的 primary，key 不是别名时返回自身
+func (c *Cache[K, V]) resolveUnlock(key K) K {
+	if primary, ok := c.aliases[key]; ok {
+		return primary
+	}
+	return key
+}
+
+func (c *Cache[K, V]) removeAliasUnlock(alias K) {
+	primary, ok := c.aliases[alias]
+	if !ok {
+		return
+	}
+	delete(c.aliases, alias)
+	delete(c.aliasesOf[primary], alias)
+	if len(c.aliasesOf[primary]) == 0 {
+		delete(c.aliasesOf, primary)
+	}
+}
+
+// dropAliasesUnlock primary 被移除时删除它的全部别名
+func (c *Cache[K, V]) dropAliasesUnlock(primary K) {
+	for alias := range c.aliasesOf[primary] {
+		delete(c.aliases, alias)
+	}
+	delete(c.aliasesOf, primary)
+}
@@ -0,0 +1,40 @@
+package lru
+
+import "testing"
+
+func TestCache_Alias(t *testing.T) {
+	cache := New[string, string](10, nil, nil)
+	if cache.Alias("/index.html", "/") {
+		panic("primary not exist")
+	}
+	cache.Put("/", "home")
+	cache.Put("/about", "about")
+	if !cache.Alias("/index.html", "/") || !cache.Alias("/home", "/index.html") {
+		panic("alias failed")
+	}
+	if cache.Alias("/about", "/") || cache.Alias("/", "/home") {
+		panic("alias conflicts with key")
+	}
+
+	if value, ok := cache.Get("/home"); !ok || value != "home" {
+		panic(value)
+	}
+	cache.Put("/index.html", "new home")
+	if value, _ := cache.GetNoMove("/"); value != "new home" || cache.Number() != 2 {
+		panic(value)
+	}
+
+	cache.RemoveAlias("/home")
+	if _, ok := cache.Get("/home"); ok {
+		panic("alias not removed")
+	}
+
+	// 通过别名移除 primary，别名随之删除
+	cache.Remove("/index.html")
+	if _, ok := cache.Get("/"); ok {
+		panic("primary not removed")
+	}
+	if len(cache.aliases) != 0 || len(cache.aliasesOf) != 0 {
+		panic(cache.aliases)
+	}
+}
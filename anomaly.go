@@ -0,0 +1,130 @@
+package lru
+
+import "time"
+
+// AnomalyKind 访问模式异常的类型
+type AnomalyKind int
+
+const (
+	AnomalyNone    AnomalyKind = iota // 不是异常事件
+	AnomalyScan                       // 顺序扫描：未命中的 key 几乎都只出现一次，扫描会冲刷缓存中的热点
+	AnomalyHotMiss                    // 单个 key 占据大量未命中，通常是该 key 无法缓存或被反复失效
+)
+
+var anomalyKindNames = [...]string{"none", "scan", "hot-miss"}
+
+func (k AnomalyKind) String() string {
+	if k < 0 || int(k) >= len(anomalyKindNames) {
+		return "unknown"
+	}
+	return anomalyKindNames[k]
+}
+
+// AnomalySink MetricsSink 可以额外实现该接口以接收访问模式异常
+type AnomalySink interface {
+	Anomaly(kind AnomalyKind)
+}
+
+// AnomalyConfig 访问模式异常检测的配置，零值字段使用默认值
+type AnomalyConfig struct {
+	Window       time.Duration // 检测窗口，默认 1 分钟
+	MinMisses    int           // 窗口内未命中少于该值时不检测，默认 100
+	ScanShare    float64       // 只未命中一次的 key 占未命中的比例不低于该值时视为扫描，默认 0.9
+	HotMissShare float64       // 单个 key 占未命中的比例不低于该值时视为热点未命中，默认 0.5
+	MaxTracked   int           // 每个窗口最多记录的不同 key 数目，超出的 key 视为只出现一次，默认 10000
+}
+
+// WithAnomalyDetection 按窗口统计 Get 和 Do 的未命中，发现顺序扫描或单个 key 占据大量未命中时，
+// 向监听发送 EventAnomaly 事件（Key 为热点未命中的 key，扫描时为零值），并通知实现了 AnomalySink 的 MetricsSink，
+// 便于运维在命中率下跌之前介入，例如开启准入过滤。每个窗口每种异常最多报告一次。
+// 检测是惰性的，窗口在 Get 时滚动，上一个窗口的结果在下一个窗口的第一次 Get 时报告
+func WithAnomalyDetection[K comparable, V interface{}](config AnomalyConfig) Option[K, V] {
+	return func(o *options[K, V]) {
+		if config.Window <= 0 {
+			config.Window = time.Minute
+		}
+		if config.MinMisses <= 0 {
+			config.MinMisses = 100
+		}
+		if config.ScanShare <= 0 {
+			config.ScanShare = 0.9
+		}
+		if config.HotMissShare <= 0 {
+			config.HotMissShare = 0.5
+		}
+		if config.MaxTracked <= 0 {
+			config.MaxTracked = 10000
+		}
+		o.anomaly = &config
+	}
+}
+
+// anomalyState 当前窗口的未命中统计
+type anomalyState[K comparable] struct {
+	start     time.Time
+	misses    int
+	untracked int // 超出 MaxTracked 未记录的未命中数
+	counts    map[K]int
+}
+
+// anomalyMissUnlock 记录一次未命中
+func (c *Cache[K, V]) anomalyMissUnlock(key K) {
+	if c.anomaly == nil {
+		return
+	}
+	s := &c.anomalies
+	s.misses++
+	if _, ok := s.counts[key]; !ok && len(s.counts) >= c.anomaly.MaxTracked {
+		s.untracked++
+		return
+	}
+	if s.counts == nil {
+		s.counts = map[K]int{}
+	}
+	s.counts[key]++
+}
+
+// anomalyRollUnlock 窗口结束时检测并开始新的窗口
+func (c *Cache[K, V]) anomalyRollUnlock() {
+	if c.anomaly == nil {
+		c.anomalies = anomalyState[K]{}
+		return
+	}
+	now := time.Now()
+	s := &c.anomalies
+	if s.start.IsZero() {
+		s.start = now
+		return
+	}
+	if now.Sub(s.start) < c.anomaly.Window {
+		return
+	}
+	if s.misses >= c.anomaly.MinMisses {
+		singles := s.untracked
+		var hotKey K
+		hotCount := 0
+		for key, n := range s.counts {
+			if n == 1 {
+				singles++
+			}
+			if n > hotCount {
+				hotKey, hotCount = key, n
+			}
+		}
+		if float64(singles) >= c.anomaly.ScanShare*float64(s.misses) {
+			var zero K
+			c.reportAnomalyUnlock(AnomalyScan, zero)
+		}
+		if float64(hotCount) >= c.anomaly.HotMissShare*float64(s.misses) {
+			c.reportAnomalyUnlock(AnomalyHotMiss, hotKey)
+		}
+	}
+	*s = anomalyState[K]{start: now}
+}
+
+func (c *Cache[K, V]) reportAnomalyUnlock(kind AnomalyKind, key K) {
+	if sink, ok := c.metrics.(AnomalySink); ok {
+		sink.Anomaly(kind)
+	}
+	c.dispatchUnlock(Event[K, V]{Type: EventAnomaly, Key: key, Anomaly: kind})
+}
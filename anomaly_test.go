@@ -0,0 +1,64 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+type anomalyCounter struct {
+	kinds []AnomalyKind
+}
+
+func (s *anomalyCounter) Hit()                      {}
+func (s *anomalyCounter) Miss()                     {}
+func (s *anomalyCounter) Remove(EventType)          {}
+func (s *anomalyCounter) Load(time.Duration, error) {}
+func (s *anomalyCounter) Anomaly(kind AnomalyKind)  { s.kinds = append(s.kinds, kind) }
+
+func TestCache_WithAnomalyDetection(t *testing.T) {
+	sink := &anomalyCounter{}
+	cache := New[int, int](10, nil, nil,
+		WithAnomalyDetection[int, int](AnomalyConfig{Window: 20 * time.Millisecond, MinMisses: 50}),
+		WithMetricsSink[int, int](sink))
+	var events []Event[int, int]
+	cache.AddListener(func(e Event[int, int]) {
+		if e.Type == EventAnomaly {
+			events = append(events, e)
+		}
+	}, ListenerConfig{})
+
+	// 扫描
+	for i := 0; i < 100; i++ {
+		cache.Get(i)
+	}
+	time.Sleep(25 * time.Millisecond)
+	cache.Get(-1)
+	if len(events) != 1 || events[0].Anomaly != AnomalyScan || len(sink.kinds) != 1 || sink.kinds[0] != AnomalyScan {
+		panic(events)
+	}
+
+	// 热点未命中
+	for i := 0; i < 100; i++ {
+		cache.Get(7)
+		if i%10 == 0 {
+			cache.Get(1000 + i)
+		}
+	}
+	time.Sleep(25 * time.Millisecond)
+	cache.Get(-1)
+	t.Log(events, events[1].Anomaly)
+	if len(events) != 2 || events[1].Anomaly != AnomalyHotMiss || events[1].Key != 7 {
+		panic(events)
+	}
+
+	// 未命中太少不检测
+	cache.Put(1, 1)
+	for i := 0; i < 100; i++ {
+		cache.Get(1)
+	}
+	time.Sleep(25 * time.Millisecond)
+	cache.Get(1)
+	if len(events) != 2 {
+		panic(events)
+	}
+}
@@ -0,0 +1,102 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// WithPersistence New 时从 path 加载快照，之后每隔 interval 保存一次快照，Close 时再保存一次
+// 快照不存在时从空缓存开始；快照损坏、版本不一致或无法解密时打印警告并从空缓存开始，不影响创建。
+// interval <= 0 时只在 Close 时保存。保存失败不会停止定期保存，最近一次的错误见 PersistenceError。
+// 只在 New 时生效，Configure 修改无效
+func WithPersistence[K comparable, V interface{}](path string, interval time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.persistPath = path
+		o.persistInterval = interval
+	}
+}
+
+// persister 自动持久化的状态
+type persister struct {
+	path string
+	stop chan struct{}
+	done chan struct{}
+
+	lock   sync.Mutex
+	err    error // 最近一次保存的错误
+	closed bool
+}
+
+// startPersistence 加载快照并启动定期保存
+func (c *Cache[K, V]) startPersistence() {
+	p := &persister{path: c.persistPath}
+	if err := c.LoadFromFile(p.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("lru: ignoring snapshot %s: %v", p.path, err)
+	}
+	if c.persistInterval > 0 {
+		p.stop = make(chan struct{})
+		p.done = make(chan struct{})
+		interval := c.persistInterval
+		go c.pprofDo(context.Background(), "persist", func(context.Context) { c.persistLoop(p, interval) })
+	}
+	c.persist = p
+}
+
+func (c *Cache[K, V]) persistLoop(p *persister, interval time.Duration) {
+	defer close(p.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.save(c.SaveToFile(p.path))
+		}
+	}
+}
+
+func (p *persister) save(err error) {
+	p.lock.Lock()
+	p.err = err
+	p.lock.Unlock()
+}
+
+// PersistenceError 返回 WithPersistence 最近一次保存快照的错误
+func (c *Cache[K, V]) PersistenceError() error {
+	if c.persist == nil {
+		return nil
+	}
+	c.persist.lock.Lock()
+	defer c.persist.lock.Unlock()
+	return c.persist.err
+}
+
+// closeStorage 停止定期保存并保存最后一次快照，然后关闭预写日志
+func (c *Cache[K, V]) closeStorage() error {
+	var err error
+	if p := c.persist; p != nil {
+		p.lock.Lock()
+		closed := p.closed
+		p.closed = true
+		p.lock.Unlock()
+		if !closed {
+			if p.stop != nil {
+				close(p.stop)
+				<-p.done
+			}
+			err = c.SaveToFile(p.path)
+			p.save(err)
+		}
+	}
+	if walErr := c.CloseWAL(); err == nil {
+		err = walErr
+	}
+	return err
+}
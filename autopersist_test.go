@@ -0,0 +1,50 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache_WithPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snapshot")
+
+	cache := New[string, int](10, nil, nil, WithPersistence[string, int](path, 10*time.Millisecond))
+	cache.Put("a", 1)
+	for deadline := time.Now().Add(time.Second); ; {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			panic("not saved periodically")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cache.Put("b", 2)
+	if err := cache.Close(); err != nil {
+		panic(err)
+	}
+	if err := cache.Close(); err != nil {
+		panic(err)
+	}
+
+	restored := New[string, int](10, nil, nil, WithPersistence[string, int](path, 0))
+	if value, _ := restored.Get("b"); value != 2 || restored.Number() != 2 {
+		panic(restored.AllKeys())
+	}
+	if restored.PersistenceError() != nil {
+		panic(restored.PersistenceError())
+	}
+
+	// 损坏的快照被忽略
+	if err := os.WriteFile(path, []byte("garbage"), 0o644); err != nil {
+		panic(err)
+	}
+	corrupted := New[string, int](10, nil, nil, WithPersistence[string, int](path, 0))
+	if corrupted.Number() != 0 {
+		panic(corrupted.AllKeys())
+	}
+}
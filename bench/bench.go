@@ -0,0 +1,194 @@
+// Package bench 提供标准化的并发负载，用于比较 LRU 缓存与 sync.Map、map + mutex 等实现的吞吐和命中率
+// 负载按 cache-aside 方式访问：读未命中时写入。Benchmark 可以直接用在 go test -bench 中发现性能回退，
+// Replay 重放 ARC、Twitter 等格式的 trace 文件，用真实的访问模式比较命中率
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"testing"
+	"text/tabwriter"
+	"time"
+
+	lru "github.com/madokast/LRU"
+)
+
+// Target 被测缓存，*lru.Cache[int, int] 满足该接口
+type Target = lru.Cacher[int, int]
+
+// Config 负载配置
+type Config struct {
+	Name       string
+	Goroutines int     // 并发数，默认 1
+	Ops        int     // 每个 goroutine 的操作数
+	ReadRatio  float64 // 读操作比例，其余为写
+	Seed       int64
+}
+
+// Result 一次运行的结果
+type Result struct {
+	Name     string
+	Ops      int
+	Hits     int
+	Misses   int
+	Duration time.Duration
+}
+
+// HitRatio 读操作的命中率
+func (r Result) HitRatio() float64 {
+	if r.Hits+r.Misses == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(r.Hits+r.Misses)
+}
+
+// OpsPerSecond 每秒操作数
+func (r Result) OpsPerSecond() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Ops) / r.Duration.Seconds()
+}
+
+// access 执行一次 cache-aside 访问，返回是否为读以及是否命中
+func access(target Target, key int, rng *rand.Rand, readRatio float64) (read, hit bool) {
+	if rng.Float64() >= readRatio {
+		target.Put(key, key)
+		return false, false
+	}
+	if _, ok := target.Get(key); ok {
+		return true, true
+	}
+	target.Put(key, key)
+	return true, false
+}
+
+// Run 对 target 执行负载并统计结果
+func Run(target Target, newWorkload NewWorkload, config Config) Result {
+	if config.Goroutines <= 0 {
+		config.Goroutines = 1
+	}
+	results := make([]Result, config.Goroutines)
+	var wg sync.WaitGroup
+	wg.Add(config.Goroutines)
+	start := time.Now()
+	for i := 0; i < config.Goroutines; i++ {
+		go func(r *Result, seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			workload := newWorkload(rng)
+			for n := 0; n < config.Ops; n++ {
+				read, hit := access(target, workload.Next(), rng, config.ReadRatio)
+				if hit {
+					r.Hits++
+				} else if read {
+					r.Misses++
+				}
+			}
+		}(&results[i], config.Seed+int64(i))
+	}
+	wg.Wait()
+
+	result := Result{Name: config.Name, Ops: config.Goroutines * config.Ops, Duration: time.Since(start)}
+	for _, r := range results {
+		result.Hits += r.Hits
+		result.Misses += r.Misses
+	}
+	return result
+}
+
+// Benchmark 在 b.RunParallel 中执行负载，并以 hit-ratio 指标报告命中率
+func Benchmark(b *testing.B, target Target, newWorkload NewWorkload, readRatio float64) {
+	var lock sync.Mutex
+	var seed int64
+	var hits, reads int
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		lock.Lock()
+		seed++
+		rng := rand.New(rand.NewSource(seed))
+		lock.Unlock()
+
+		workload := newWorkload(rng)
+		h, r := 0, 0
+		for pb.Next() {
+			read, hit := access(target, workload.Next(), rng, readRatio)
+			if read {
+				r++
+			}
+			if hit {
+				h++
+			}
+		}
+
+		lock.Lock()
+		hits += h
+		reads += r
+		lock.Unlock()
+	})
+	if reads > 0 {
+		b.ReportMetric(float64(hits)/float64(reads), "hit-ratio")
+	}
+}
+
+// WriteReport 以表格形式输出结果
+func WriteReport(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tops\tops/s\thit_ratio")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%.0f\t%.4f\n", r.Name, r.Ops, r.OpsPerSecond(), r.HitRatio())
+	}
+	return tw.Flush()
+}
+
+// MutexMap map + mutex 实现的无淘汰缓存，作为对照
+type MutexMap struct {
+	lock sync.Mutex
+	m    map[int]int
+}
+
+func NewMutexMap() *MutexMap {
+	return &MutexMap{m: map[int]int{}}
+}
+
+func (mm *MutexMap) Put(key int, value int) {
+	mm.lock.Lock()
+	mm.m[key] = value
+	mm.lock.Unlock()
+}
+
+func (mm *MutexMap) Get(key int) (value int, ok bool) {
+	mm.lock.Lock()
+	value, ok = mm.m[key]
+	mm.lock.Unlock()
+	return value, ok
+}
+
+func (mm *MutexMap) Remove(key int) {
+	mm.lock.Lock()
+	delete(mm.m, key)
+	mm.lock.Unlock()
+}
+
+// SyncMap sync.Map 实现的无淘汰缓存，作为对照
+type SyncMap struct {
+	m sync.Map
+}
+
+func (sm *SyncMap) Put(key int, value int) {
+	sm.m.Store(key, value)
+}
+
+func (sm *SyncMap) Get(key int) (value int, ok bool) {
+	v, ok := sm.m.Load(key)
+	if !ok {
+		return 0, false
+	}
+	return v.(int), true
+}
+
+func (sm *SyncMap) Remove(key int) {
+	sm.m.Delete(key)
+}
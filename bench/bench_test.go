@@ -0,0 +1,50 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	lru "github.com/madokast/LRU"
+)
+
+func TestRun(t *testing.T) {
+	// 容量足够时 zipf 负载命中率很高，循环扫描超过容量时 LRU 命中率为 0
+	zipf := Run(lru.New[int, int](100, nil, nil), Zipf(1000, 1.2), Config{Name: "zipf", Goroutines: 4, Ops: 1000, ReadRatio: 1})
+	scan := Run(lru.New[int, int](100, nil, nil), Scan(1000), Config{Name: "scan", Ops: 5000, ReadRatio: 1})
+	uniform := Run(NewMutexMap(), Uniform(100), Config{Name: "uniform", Ops: 5000, ReadRatio: 0.9})
+	if zipf.Ops != 4000 || zipf.HitRatio() < 0.5 {
+		panic(zipf)
+	}
+	if scan.Hits != 0 {
+		panic(scan)
+	}
+	if uniform.HitRatio() < 0.9 || uniform.Hits+uniform.Misses >= uniform.Ops {
+		panic(uniform)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, []Result{zipf, scan, uniform}); err != nil {
+		panic(err)
+	}
+	if !strings.Contains(buf.String(), "scan") {
+		panic(buf.String())
+	}
+	t.Log("\n" + buf.String())
+}
+
+func BenchmarkLRU_Zipf(b *testing.B) {
+	Benchmark(b, lru.New[int, int](1000, nil, nil), Zipf(100000, 1.1), 0.9)
+}
+
+func BenchmarkLRU_Uniform(b *testing.B) {
+	Benchmark(b, lru.New[int, int](1000, nil, nil), Uniform(10000), 0.9)
+}
+
+func BenchmarkMutexMap_Zipf(b *testing.B) {
+	Benchmark(b, NewMutexMap(), Zipf(100000, 1.1), 0.9)
+}
+
+func BenchmarkSyncMap_Zipf(b *testing.B) {
+	Benchmark(b, &SyncMap{}, Zipf(100000, 1.1), 0.9)
+}
@@ -0,0 +1,131 @@
+package bench
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TraceOp 访问记录的操作类型
+type TraceOp int
+
+const (
+	TraceGet    TraceOp = iota // 读，未命中时写入
+	TraceSet                   // 写
+	TraceDelete                // 删除
+)
+
+// TraceRecord 一条访问记录
+type TraceRecord struct {
+	Key int
+	Op  TraceOp
+}
+
+// ParseARCTrace 解析 ARC 论文使用的 trace 文件
+// 每行为 "起始块号 块数 忽略 请求号"，表示依次读取从起始块号开始的连续若干块
+func ParseARCTrace(r io.Reader) ([]TraceRecord, error) {
+	var records []TraceRecord
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("bench: arc trace line %d: too few fields", line)
+		}
+		start, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("bench: arc trace line %d: %w", line, err)
+		}
+		count, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("bench: arc trace line %d: %w", line, err)
+		}
+		for i := 0; i < count; i++ {
+			records = append(records, TraceRecord{Key: start + i, Op: TraceGet})
+		}
+	}
+	return records, scanner.Err()
+}
+
+// ParseTwitterTrace 解析 Twitter 缓存 trace（twitter/cache-trace）的 CSV 文件
+// 每行为 "时间戳,key,key 大小,value 大小,客户端 id,操作,TTL"，字符串 key 按首次出现的顺序编号。
+// get/gets 视为读，set/add/replace/cas/append/prepend/incr/decr 视为写，delete 视为删除，其余操作忽略
+func ParseTwitterTrace(r io.Reader) ([]TraceRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+	ids := map[string]int{}
+	var records []TraceRecord
+	for line := 1; ; line++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("bench: twitter trace: %w", err)
+		}
+		if len(fields) < 6 {
+			return nil, fmt.Errorf("bench: twitter trace line %d: too few fields", line)
+		}
+		var op TraceOp
+		switch fields[5] {
+		case "get", "gets":
+			op = TraceGet
+		case "set", "add", "replace", "cas", "append", "prepend", "incr", "decr":
+			op = TraceSet
+		case "delete":
+			op = TraceDelete
+		default:
+			continue
+		}
+		id, ok := ids[fields[1]]
+		if !ok {
+			id = len(ids)
+			ids[fields[1]] = id
+		}
+		records = append(records, TraceRecord{Key: id, Op: op})
+	}
+}
+
+// Replay 按顺序对 target 重放访问记录，用于比较不同缓存在真实访问模式下的命中率
+func Replay(target Target, records []TraceRecord) Result {
+	result := Result{Name: "replay", Ops: len(records)}
+	start := time.Now()
+	for _, record := range records {
+		switch record.Op {
+		case TraceGet:
+			if _, ok := target.Get(record.Key); ok {
+				result.Hits++
+			} else {
+				result.Misses++
+				target.Put(record.Key, record.Key)
+			}
+		case TraceSet:
+			target.Put(record.Key, record.Key)
+		case TraceDelete:
+			target.Remove(record.Key)
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+// FromTrace 从随机位置开始循环产生访问记录中的 key，忽略操作类型，用于 Run 和 Benchmark
+func FromTrace(records []TraceRecord) NewWorkload {
+	return func(rng *rand.Rand) Workload {
+		next := rng.Intn(len(records))
+		return workloadFunc(func() int {
+			key := records[next].Key
+			next = (next + 1) % len(records)
+			return key
+		})
+	}
+}
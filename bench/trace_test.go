@@ -0,0 +1,53 @@
+package bench
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	lru "github.com/madokast/LRU"
+)
+
+func TestParseARCTrace(t *testing.T) {
+	records, err := ParseARCTrace(strings.NewReader("10 3 0 0\n\n5 1 0 1\n"))
+	if err != nil {
+		panic(err)
+	}
+	expect := []TraceRecord{{Key: 10}, {Key: 11}, {Key: 12}, {Key: 5}}
+	if !reflect.DeepEqual(records, expect) {
+		panic(records)
+	}
+	if _, err = ParseARCTrace(strings.NewReader("x 1 0 0\n")); err == nil {
+		panic("expect error")
+	}
+}
+
+func TestParseTwitterTrace(t *testing.T) {
+	trace := `0,a,1,10,1,get,0
+1,b,1,10,1,set,0
+2,a,1,10,1,gets,0
+3,b,1,0,1,delete,0
+4,c,1,0,1,unknown,0
+`
+	records, err := ParseTwitterTrace(strings.NewReader(trace))
+	if err != nil {
+		panic(err)
+	}
+	expect := []TraceRecord{{0, TraceGet}, {1, TraceSet}, {0, TraceGet}, {1, TraceDelete}}
+	if !reflect.DeepEqual(records, expect) {
+		panic(records)
+	}
+
+	result := Replay(lru.New[int, int](10, nil, nil), records)
+	if result.Hits != 1 || result.Misses != 1 {
+		panic(result)
+	}
+
+	w := FromTrace(records)(rand.New(rand.NewSource(1)))
+	for i := 0; i < 10; i++ {
+		if key := w.Next(); key != 0 && key != 1 {
+			panic(key)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package bench
+
+import "math/rand"
+
+// Workload 生成访问的 key 序列，不需要并发安全，每个 goroutine 使用独立的实例
+type Workload interface {
+	Next() int
+}
+
+// NewWorkload 由随机数生成器创建 Workload
+type NewWorkload func(rng *rand.Rand) Workload
+
+type workloadFunc func() int
+
+func (f workloadFunc) Next() int {
+	return f()
+}
+
+// Zipf key 服从参数为 s 的 zipf 分布，s > 1，越大越集中于少数热点 key
+func Zipf(keySpace int, s float64) NewWorkload {
+	return func(rng *rand.Rand) Workload {
+		zipf := rand.NewZipf(rng, s, 1, uint64(keySpace-1))
+		return workloadFunc(func() int { return int(zipf.Uint64()) })
+	}
+}
+
+// Uniform key 在 [0, keySpace) 中均匀分布
+func Uniform(keySpace int) NewWorkload {
+	return func(rng *rand.Rand) Workload {
+		return workloadFunc(func() int { return rng.Intn(keySpace) })
+	}
+}
+
+// Scan 从随机位置开始顺序循环访问 [0, keySpace)，keySpace 大于缓存容量时 LRU 命中率为 0
+func Scan(keySpace int) NewWorkload {
+	return func(rng *rand.Rand) Workload {
+		next := rng.Intn(keySpace)
+		return workloadFunc(func() int {
+			key := next
+			next = (next + 1) % keySpace
+			return key
+		})
+	}
+}
+
+// Hotspot hotFraction 比例的热点 key 占 hotProb 比例的访问，热点 key 为 [0, keySpace*hotFraction)
+func Hotspot(keySpace int, hotFraction, hotProb float64) NewWorkload {
+	hot := int(float64(keySpace) * hotFraction)
+	if hot < 1 {
+		hot = 1
+	}
+	return func(rng *rand.Rand) Workload {
+		return workloadFunc(func() int {
+			if rng.Float64() < hotProb || hot == keySpace {
+				return rng.Intn(hot)
+			}
+			return hot + rng.Intn(keySpace-hot)
+		})
+	}
+}
+
+// SlidingWindow key 在大小为 window 的窗口内均匀分布，每 shift 次访问窗口向后滑动 1，模拟热点随时间漂移
+func SlidingWindow(window, shift int) NewWorkload {
+	return func(rng *rand.Rand) Workload {
+		n := 0
+		return workloadFunc(func() int {
+			offset := n / shift
+			n++
+			return offset + rng.Intn(window)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package bench
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	w := Scan(3)(rand.New(rand.NewSource(1)))
+	first := w.Next()
+	if w.Next() != (first+1)%3 || w.Next() != (first+2)%3 || w.Next() != first {
+		panic("not sequential")
+	}
+}
+
+func TestHotspot(t *testing.T) {
+	w := Hotspot(1000, 0.1, 0.9)(rand.New(rand.NewSource(1)))
+	hot := 0
+	for i := 0; i < 10000; i++ {
+		if key := w.Next(); key < 100 {
+			hot++
+		} else if key >= 1000 {
+			panic(key)
+		}
+	}
+	if hot < 8500 || hot > 9500 {
+		panic(hot)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	w := SlidingWindow(10, 5)(rand.New(rand.NewSource(1)))
+	for i := 0; i < 100; i++ {
+		offset := i / 5
+		if key := w.Next(); key < offset || key >= offset+10 {
+			panic(key)
+		}
+	}
+}
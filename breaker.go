@@ -0,0 +1,136 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen 熔断打开且没有旧值可用时 Do 返回的错误
+var ErrCircuitOpen = errors.New("lru: circuit breaker open")
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // 正常调用加载函数
+	CircuitOpen                         // 不调用加载函数，返回旧值或 ErrCircuitOpen
+	CircuitHalfOpen                     // 冷却结束，只放行一次试探加载
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCircuitBreaker Do 的加载函数连续失败 failures 次后熔断，cooldown 内不再调用加载函数
+// 熔断期间 Do 返回已过期或已失效的旧值，没有旧值时返回 ErrCircuitOpen。为了保留旧值，开启后 Do 遇到的过期项不会被移除，
+// 直到重新加载成功后被覆盖。cooldown 结束后放行一次试探加载，成功则恢复，失败则重新熔断。状态见 Stats().Circuit
+func WithCircuitBreaker[K comparable, V interface{}](failures int, cooldown time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.breakerFailures = failures
+		o.breakerCooldown = cooldown
+	}
+}
+
+// breaker 熔断器，failures <= 0 时不熔断
+type breaker struct {
+	lock      sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     CircuitState
+	failures  int // 连续失败次数
+	openedAt  time.Time
+	probing   bool   // 半开状态下是否已放行试探加载
+	opens     uint64 // 累计熔断次数
+}
+
+func (b *breaker) configure(threshold int, cooldown time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.threshold, b.cooldown = threshold, cooldown
+	if threshold <= 0 {
+		b.state, b.failures, b.probing = CircuitClosed, 0, false
+	}
+}
+
+// allow 是否可以调用加载函数，允许时调用方必须随后调用 report
+func (b *breaker) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state, b.probing = CircuitHalfOpen, true
+		return true
+	case CircuitHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// report 报告一次加载的结果
+func (b *breaker) report(err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.threshold <= 0 {
+		return
+	}
+	b.probing = false
+	if err == nil {
+		b.state, b.failures = CircuitClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		if b.state != CircuitOpen {
+			b.opens++
+		}
+		b.state, b.openedAt = CircuitOpen, time.Now()
+	}
+}
+
+func (b *breaker) stats() (CircuitState, uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state, b.opens
+}
+
+// staleValue Do 未命中时仍留在缓存中的旧值
+type staleValue[K comparable, V interface{}] struct {
+	entry     *Entry[K, V]
+	value     V
+	expired   bool // 过期，否则为 Invalidate 标记的失效
+	validator func(ctx context.Context, key K, value V) (bool, error)
+}
+
+// getForDo 同 Get，但开启熔断或校验时保留过期项，并把过期或已失效的旧值返回给调用方备用
+func (c *Cache[K, V]) getForDo(key K) (value V, ok bool, stale *staleValue[K, V]) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if ele, found := c.m[c.resolveUnlock(key)]; found && (c.breakerFailures > 0 || c.validator != nil) {
+		e := ele.Value.(*Entry[K, V])
+		if expired := e.expired(time.Now()); expired || e.stale {
+			c.miss()
+			return value, false, &staleValue[K, V]{entry: e, value: e.value, expired: expired, validator: c.validator}
+		}
+	}
+	value, ok = c.getUnlock(key)
+	return value, ok, nil
+}
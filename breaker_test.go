@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_WithCircuitBreaker(t *testing.T) {
+	cache := New[int, int](10, nil, nil, WithCircuitBreaker[int, int](2, 50*time.Millisecond))
+	errStore := errors.New("store down")
+	calls := 0
+	failing := func() (int, error) {
+		calls++
+		return 0, errStore
+	}
+
+	cache.PutWithTTL(1, 100, time.Millisecond)
+	time.Sleep(2 * time.Millisecond)
+
+	// 连续失败 2 次后熔断
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Do(1, failing, 0); !errors.Is(err, errStore) {
+			panic(err)
+		}
+	}
+	if stats := cache.Stats(); stats.Circuit != CircuitOpen || stats.CircuitOpens != 1 {
+		panic(stats.Circuit)
+	}
+
+	// 熔断期间返回过期的旧值，没有旧值时返回 ErrCircuitOpen
+	if value, err := cache.Do(1, failing, 0); value != 100 || err != nil {
+		panic(err)
+	}
+	if _, err := cache.Do(2, failing, 0); !errors.Is(err, ErrCircuitOpen) {
+		panic(err)
+	}
+	if calls != 2 {
+		panic(calls)
+	}
+
+	// 冷却结束后试探加载成功，恢复正常
+	time.Sleep(60 * time.Millisecond)
+	value, err := cache.Do(1, func() (int, error) { return 200, nil }, 0)
+	if value != 200 || err != nil || cache.Stats().Circuit != CircuitClosed {
+		panic(err)
+	}
+	t.Log(cache.Stats().Circuit)
+}
@@ -0,0 +1,142 @@
+// Package broker 让同一台机器上的多个进程共享一个缓存：一个进程持有 *lru.Cache[string, []byte] 并通过 Unix socket 提供服务，
+// 其他进程用 Client 访问，方法与 Cache 对应。协议是简单的二进制请求-回复，每个连接上的请求依次处理，不需要额外的 sidecar
+package broker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"os"
+	"sync"
+
+	lru "github.com/madokast/LRU"
+)
+
+// Server 通过 Unix socket 提供缓存服务
+type Server struct {
+	cache *lru.Cache[string, []byte]
+
+	lock      sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewServer 创建服务，cache 仍可以在本进程中直接使用
+func NewServer(cache *lru.Cache[string, []byte]) *Server {
+	return &Server{cache: cache, listeners: map[net.Listener]struct{}{}, conns: map[net.Conn]struct{}{}}
+}
+
+// ListenAndServe 监听 Unix socket path 并提供服务，path 已存在时先删除。Close 之前不返回
+func (s *Server) ListenAndServe(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve 在 l 上接受连接，每个连接一个 goroutine。Close 之后返回 nil
+func (s *Server) Serve(l net.Listener) error {
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		_ = l.Close()
+		return nil
+	}
+	s.listeners[l] = struct{}{}
+	s.lock.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			s.lock.Lock()
+			closed := s.closed
+			delete(s.listeners, l)
+			s.lock.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.lock.Lock()
+		if s.closed {
+			s.lock.Unlock()
+			_ = conn.Close()
+			return nil
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.lock.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer func() {
+		s.lock.Lock()
+		delete(s.conns, conn)
+		s.lock.Unlock()
+		_ = conn.Close()
+		s.wg.Done()
+	}()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		req, err := readRequest(r)
+		if err != nil {
+			return
+		}
+		if err = writeResponse(w, s.handle(req)); err == nil && r.Buffered() == 0 {
+			err = w.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handle(req *request) *response {
+	switch req.op {
+	case opGet:
+		if value, ok := s.cache.Get(req.key); ok {
+			return &response{status: statusOK, payload: value}
+		}
+		return &response{status: statusNotFound}
+	case opPut:
+		if err := s.cache.TryPut(req.key, req.value, req.ttl); err != nil {
+			return &response{status: statusError, payload: []byte(err.Error())}
+		}
+	case opRemove:
+		s.cache.Remove(req.key)
+	case opRemoveAll:
+		s.cache.RemoveAll()
+	case opNumber:
+		return &response{status: statusOK, payload: binary.BigEndian.AppendUint64(nil, uint64(s.cache.Number()))}
+	case opSize:
+		return &response{status: statusOK, payload: binary.BigEndian.AppendUint64(nil, uint64(s.cache.Size()))}
+	default:
+		return &response{status: statusError, payload: []byte("unknown op")}
+	}
+	return &response{status: statusOK}
+}
+
+// Close 停止接受连接，关闭已有连接并等待它们的 goroutine 退出
+func (s *Server) Close() error {
+	s.lock.Lock()
+	s.closed = true
+	for l := range s.listeners {
+		_ = l.Close()
+	}
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.lock.Unlock()
+	s.wg.Wait()
+	return nil
+}
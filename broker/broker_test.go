@@ -0,0 +1,166 @@
+package broker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	lru "github.com/madokast/LRU"
+)
+
+// socketPath Unix socket 路径长度有限，不使用可能很长的 t.TempDir
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "broker")
+	if err != nil {
+		panic(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "cache.sock")
+}
+
+// dialRetry 等待服务端开始监听后连接
+func dialRetry(path string) *Client {
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(5 * time.Millisecond) {
+		client, err := Dial(path, time.Second)
+		if err == nil {
+			return client
+		}
+		if time.Now().After(deadline) {
+			panic(err)
+		}
+	}
+}
+
+func TestBroker(t *testing.T) {
+	path := socketPath(t)
+	cache := lru.New[string, []byte](10, nil, func(key string, value []byte) int { return len(value) },
+		lru.WithSizePolicy[string, []byte](lru.SizeStrict))
+	server := NewServer(cache)
+	served := make(chan error)
+	go func() { served <- server.ListenAndServe(path) }()
+
+	client := dialRetry(path)
+	defer client.Close()
+
+	if err := client.Put("a", []byte("xy")); err != nil {
+		panic(err)
+	}
+	if err := client.PutWithTTL("b", []byte("z"), time.Hour); err != nil {
+		panic(err)
+	}
+	if value, ok, err := client.Get("a"); err != nil || !ok || string(value) != "xy" {
+		panic(value)
+	}
+	if _, ok, err := client.Get("missing"); err != nil || ok {
+		panic(err)
+	}
+	if n, err := client.Number(); err != nil || n != 2 {
+		panic(n)
+	}
+	if size, err := client.Size(); err != nil || size != 3 {
+		panic(size)
+	}
+
+	// 服务端拒绝写入
+	err := client.Put("empty", nil)
+	var brokerErr Error
+	if !errors.As(err, &brokerErr) {
+		panic(err)
+	}
+	t.Log(err)
+
+	// 服务端进程中直接访问同一个缓存
+	if value, ok := cache.Get("b"); !ok || string(value) != "z" {
+		panic(value)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, _, err := client.Get("a"); err != nil {
+					panic(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := client.Remove("a"); err != nil {
+		panic(err)
+	}
+	if err := client.RemoveAll(); err != nil {
+		panic(err)
+	}
+	if n, _ := client.Number(); n != 0 {
+		panic(n)
+	}
+
+	if err := server.Close(); err != nil {
+		panic(err)
+	}
+	if err := <-served; err != nil {
+		panic(err)
+	}
+	if _, _, err := client.Get("a"); err == nil {
+		panic("connection still open")
+	}
+}
+
+// 客户端和服务端进程写入的同时另一个客户端读取大小，在 -race 下检查服务端读取计数时持有锁
+func TestBroker_SizeWhilePut(t *testing.T) {
+	path := socketPath(t)
+	cache := lru.New[string, []byte](100, nil, func(key string, value []byte) int { return len(value) })
+	server := NewServer(cache)
+	served := make(chan error)
+	go func() { served <- server.ListenAndServe(path) }()
+	writer, reader := dialRetry(path), dialRetry(path)
+	defer writer.Close()
+	defer reader.Close()
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			if err := writer.Put(strconv.Itoa(i%50), []byte("x")); err != nil {
+				panic(err)
+			}
+		}
+	}()
+	// 套接字的读写在 race 检测中建立了先后关系，进程内直接写入才能暴露没有加锁的读取
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			cache.Put(strconv.Itoa(i%50), []byte("y"))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			if size, err := reader.Size(); err != nil || size > 50 {
+				panic(size)
+			}
+			if n, err := reader.Number(); err != nil || n > 50 {
+				panic(n)
+			}
+		}
+	}()
+	wg.Wait()
+	if size, _ := reader.Size(); size != 50 {
+		panic(size)
+	}
+
+	if err := server.Close(); err != nil {
+		panic(err)
+	}
+	if err := <-served; err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Error 服务端返回的错误，例如服务端缓存拒绝写入
+type Error string
+
+func (e Error) Error() string {
+	return "broker: " + string(e)
+}
+
+// Client 访问 Server 的客户端，方法与 lru.Cache 对应，额外返回连接错误。并发安全，同一客户端上的请求依次执行
+type Client struct {
+	lock sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+}
+
+// Dial 连接 Unix socket path 上的 Server
+func Dial(path string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(conn), nil
+}
+
+// NewClient 在已有连接上创建客户端
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+}
+
+func (c *Client) do(req *request) (*response, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := writeRequest(c.w, req); err != nil {
+		return nil, err
+	}
+	if err := c.w.Flush(); err != nil {
+		return nil, err
+	}
+	resp, err := readResponse(c.r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.status == statusError {
+		return nil, Error(resp.payload)
+	}
+	return resp, nil
+}
+
+func (c *Client) Get(key string) (value []byte, ok bool, err error) {
+	resp, err := c.do(&request{op: opGet, key: key})
+	if err != nil || resp.status == statusNotFound {
+		return nil, false, err
+	}
+	return resp.payload, true, nil
+}
+
+func (c *Client) Put(key string, value []byte) error {
+	return c.PutWithTTL(key, value, 0)
+}
+
+// PutWithTTL ttl 精确到毫秒，<= 0 时永不过期
+func (c *Client) PutWithTTL(key string, value []byte, ttl time.Duration) error {
+	_, err := c.do(&request{op: opPut, key: key, value: value, ttl: ttl})
+	return err
+}
+
+func (c *Client) Remove(key string) error {
+	_, err := c.do(&request{op: opRemove, key: key})
+	return err
+}
+
+func (c *Client) RemoveAll() error {
+	_, err := c.do(&request{op: opRemoveAll})
+	return err
+}
+
+func (c *Client) Number() (int, error) {
+	return c.count(opNumber)
+}
+
+func (c *Client) Size() (int, error) {
+	return c.count(opSize)
+}
+
+func (c *Client) count(op byte) (int, error) {
+	resp, err := c.do(&request{op: op})
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.payload) != 8 {
+		return 0, fmt.Errorf("broker: bad reply length %d", len(resp.payload))
+	}
+	return int(binary.BigEndian.Uint64(resp.payload)), nil
+}
+
+// Close 关闭连接
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
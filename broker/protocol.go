@@ -0,0 +1,119 @@
+package broker
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// 请求的操作码
+const (
+	opGet byte = iota + 1
+	opPut
+	opRemove
+	opRemoveAll
+	opNumber
+	opSize
+)
+
+// 回复的状态
+const (
+	statusOK       byte = iota // 成功，Get 时表示命中
+	statusNotFound             // Get 未命中
+	statusError                // 失败，负载为错误信息
+)
+
+// maxFrame 单个 key 或 value 的最大长度
+const maxFrame = 64 << 20
+
+// ErrFrameTooLarge key 或 value 超过 64MB
+var ErrFrameTooLarge = errors.New("broker: frame too large")
+
+// request 请求帧：op(1) + ttl 毫秒(8) + key 长度(4) + value 长度(4) + key + value，整数为大端
+type request struct {
+	op    byte
+	ttl   time.Duration
+	key   string
+	value []byte
+}
+
+// response 回复帧：status(1) + 负载长度(4) + 负载。Number、Size 的负载为 8 字节大端整数
+type response struct {
+	status  byte
+	payload []byte
+}
+
+func writeRequest(w *bufio.Writer, req *request) error {
+	if len(req.key) > maxFrame || len(req.value) > maxFrame {
+		return ErrFrameTooLarge
+	}
+	var header [17]byte
+	header[0] = req.op
+	binary.BigEndian.PutUint64(header[1:9], uint64(req.ttl.Milliseconds()))
+	binary.BigEndian.PutUint32(header[9:13], uint32(len(req.key)))
+	binary.BigEndian.PutUint32(header[13:17], uint32(len(req.value)))
+	w.Write(header[:])
+	w.WriteString(req.key)
+	_, err := w.Write(req.value)
+	return err
+}
+
+func readRequest(r *bufio.Reader) (*request, error) {
+	var header [17]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	keyLen := binary.BigEndian.Uint32(header[9:13])
+	valueLen := binary.BigEndian.Uint32(header[13:17])
+	if keyLen > maxFrame || valueLen > maxFrame {
+		return nil, ErrFrameTooLarge
+	}
+	buf := make([]byte, int(keyLen)+int(valueLen))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, unexpected(err)
+	}
+	return &request{
+		op:    header[0],
+		ttl:   time.Duration(int64(binary.BigEndian.Uint64(header[1:9]))) * time.Millisecond,
+		key:   string(buf[:keyLen]),
+		value: buf[keyLen:],
+	}, nil
+}
+
+func writeResponse(w *bufio.Writer, resp *response) error {
+	var header [5]byte
+	header[0] = resp.status
+	binary.BigEndian.PutUint32(header[1:5], uint32(len(resp.payload)))
+	w.Write(header[:])
+	_, err := w.Write(resp.payload)
+	return err
+}
+
+func readResponse(r *bufio.Reader) (*response, error) {
+	var header [5]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[1:5])
+	if length > maxFrame {
+		return nil, ErrFrameTooLarge
+	}
+	resp := &response{status: header[0], payload: make([]byte, length)}
+	if _, err := io.ReadFull(r, resp.payload); err != nil {
+		return nil, unexpected(err)
+	}
+	if resp.status > statusError {
+		return nil, fmt.Errorf("broker: unknown status %d", resp.status)
+	}
+	return resp, nil
+}
+
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,10 @@
+package lru
+
+// Cacher 缓存的基本操作，*Cache 以及其他存储形式的缓存（如 diskcache）都实现了该接口
+type Cacher[K comparable, V interface{}] interface {
+	Put(key K, value V)
+	Get(key K) (value V, ok bool)
+	Remove(key K)
+}
+
+var _ Cacher[int, int] = (*Cache[int, int])(nil)
@@ -0,0 +1,91 @@
+package lru
+
+import "time"
+
+// ChurnStats 最近一段时间内缓存的变化速率，用于发现缓存抖动：淘汰速率高而命中率低说明缓存过小或访问模式不适合缓存
+type ChurnStats struct {
+	Window             time.Duration // 统计覆盖的时长，在一到两个 WithChurnWindow 窗口之间
+	PutsPerSecond      float64
+	EvictionsPerSecond float64
+	ReplacementRatio   float64 // 写入中覆盖已有 key 的比例
+	HitRatio           float64
+}
+
+// WithChurnWindow 按 window 统计写入、淘汰速率和覆盖比例，结果见 Stats().Churn
+// 统计是惰性的，窗口在 Get 和写入时滚动，不需要后台协程
+func WithChurnWindow[K comparable, V interface{}](window time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.churnWindow = window
+	}
+}
+
+// churnCounters 累计计数的快照
+type churnCounters struct {
+	at        time.Time
+	puts      uint64
+	replaces  uint64
+	evictions uint64
+	hits      uint64
+	misses    uint64
+}
+
+// churnState 当前窗口和上一个窗口起点的快照，速率按上一个窗口起点到现在计算
+type churnState struct {
+	puts     uint64 // 累计写入数，持有写锁时修改
+	replaces uint64 // 累计覆盖数
+	prev     churnCounters
+	cur      churnCounters
+}
+
+func (c *Cache[K, V]) churnCountersUnlock(now time.Time) churnCounters {
+	return churnCounters{
+		at:        now,
+		puts:      c.churn.puts,
+		replaces:  c.churn.replaces,
+		evictions: c.evictions,
+		hits:      c.hits.Load(),
+		misses:    c.misses.Load(),
+	}
+}
+
+// churnRollUnlock 当前窗口结束时滚动
+func (c *Cache[K, V]) churnRollUnlock() {
+	if c.churnWindow <= 0 {
+		return
+	}
+	now := time.Now()
+	if c.churn.cur.at.IsZero() {
+		c.churn.cur = c.churnCountersUnlock(now)
+		c.churn.prev = c.churn.cur
+		return
+	}
+	if now.Sub(c.churn.cur.at) < c.churnWindow {
+		return
+	}
+	c.churn.prev = c.churn.cur
+	if now.Sub(c.churn.cur.at) >= 2*c.churnWindow {
+		c.churn.prev = c.churnCountersUnlock(now.Add(-c.churnWindow)) // 中间空闲了整个窗口，之前的活动不再计入
+	}
+	c.churn.cur = c.churnCountersUnlock(now)
+}
+
+// churnStatsUnlock 计算变化速率，持有读锁即可
+func (c *Cache[K, V]) churnStatsUnlock() ChurnStats {
+	if c.churnWindow <= 0 || c.churn.prev.at.IsZero() {
+		return ChurnStats{}
+	}
+	now := c.churnCountersUnlock(time.Now())
+	base := c.churn.prev
+	stats := ChurnStats{Window: now.at.Sub(base.at)}
+	if seconds := stats.Window.Seconds(); seconds > 0 {
+		stats.PutsPerSecond = float64(now.puts-base.puts) / seconds
+		stats.EvictionsPerSecond = float64(now.evictions-base.evictions) / seconds
+	}
+	if puts := now.puts - base.puts; puts > 0 {
+		stats.ReplacementRatio = float64(now.replaces-base.replaces) / float64(puts)
+	}
+	if requests := now.hits - base.hits + now.misses - base.misses; requests > 0 {
+		stats.HitRatio = float64(now.hits-base.hits) / float64(requests)
+	}
+	return stats
+}
@@ -0,0 +1,42 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_WithChurnWindow(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	if cache.Stats().Churn != (ChurnStats{}) {
+		panic("churn not enabled")
+	}
+
+	cache = New[int, int](10, nil, nil, WithChurnWindow[int, int](time.Hour))
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i) // 淘汰 10 个
+	}
+	for i := 10; i < 15; i++ {
+		cache.Put(i, i) // 覆盖
+	}
+	cache.Get(19)
+	cache.Get(0)
+	churn := cache.Stats().Churn
+	if churn.Window <= 0 || churn.PutsPerSecond <= 0 || churn.EvictionsPerSecond <= 0 {
+		panic(churn)
+	}
+	if churn.ReplacementRatio != 0.2 || churn.HitRatio != 0.5 {
+		panic(churn)
+	}
+	t.Log(churn)
+}
+
+func TestCache_ChurnRoll(t *testing.T) {
+	cache := New[int, int](10, nil, nil, WithChurnWindow[int, int](20*time.Millisecond))
+	cache.Put(1, 1)
+	cache.Put(1, 1)
+	time.Sleep(50 * time.Millisecond) // 空闲超过两个窗口
+	cache.Get(2)
+	if churn := cache.Stats().Churn; churn.PutsPerSecond != 0 || churn.ReplacementRatio != 0 || churn.HitRatio != 0 {
+		panic(churn)
+	}
+}
@@ -0,0 +1,312 @@
+//go:build !lru_minimal
+
+// lructl 查看和转换 lru 快照文件和预写日志，重放访问记录
+//
+//	lructl info    [flags] file              打印格式、版本、是否加密、项数、过期和失效情况
+//	lructl dump    [flags] file              按最久未访问到最近访问的顺序打印全部项
+//	lructl diff    [flags] old new           打印两个文件之间新增、删除和修改的 key
+//	lructl convert [flags] in out            转换格式（-to snapshot|wal），可用于加密、解密或更换密钥
+//	lructl replay  [flags] trace             用 bench.Replay 按访问记录重放一个 -size 大小的缓存，打印命中率
+//
+// file 可以是快照或预写日志，按文件头自动识别。预写日志旁边存在 OpenWAL 的快照 file + ".snapshot" 时，
+// 先加载快照再重放日志，得到与 OpenWAL 恢复相同的内容。
+// 快照和日志使用 gob 编码，需要通过 -k/-v 指明写入时的 key/value 类型，默认均为 string
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	lru "github.com/madokast/LRU"
+	"github.com/madokast/LRU/bench"
+)
+
+// options 各子命令共用的参数
+type options struct {
+	keyType   string
+	valueType string
+	key       []byte // 读取时的解密密钥
+	outKey    []byte // convert 输出的加密密钥
+	to        string // convert 输出的格式，为空表示与输入相同
+	format    string // replay 的访问记录格式
+	size      int    // replay 的缓存大小
+	args      []string
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "lructl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("usage: lructl info|dump|diff|convert|replay [flags] files...")
+	}
+
+	cmd := args[0]
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	var opts options
+	var key, outKey string
+	fs.StringVar(&opts.keyType, "k", "string", "key 类型：string, int, int64, uint64")
+	fs.StringVar(&opts.valueType, "v", "string", "value 类型：string, bytes, int, int64, float64")
+	fs.StringVar(&key, "key", "", "解密密钥，十六进制")
+	fs.StringVar(&outKey, "out-key", "", "convert 输出的加密密钥，十六进制，为空表示不加密")
+	fs.StringVar(&opts.to, "to", "", "convert 输出的格式：snapshot, wal，为空表示与输入相同")
+	fs.StringVar(&opts.format, "format", "arc", "replay 的访问记录格式：arc, twitter")
+	fs.IntVar(&opts.size, "size", 1000, "replay 的缓存大小")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	opts.args = fs.Args()
+
+	var err error
+	if opts.key, err = hex.DecodeString(key); err != nil {
+		return fmt.Errorf("-key: %w", err)
+	}
+	if opts.outKey, err = hex.DecodeString(outKey); err != nil {
+		return fmt.Errorf("-out-key: %w", err)
+	}
+
+	wantArgs := map[string]int{"info": 1, "dump": 1, "diff": 2, "convert": 2, "replay": 1}
+	n, ok := wantArgs[cmd]
+	if !ok {
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+	if len(opts.args) != n {
+		return fmt.Errorf("%s requires %d file arguments", cmd, n)
+	}
+	switch opts.to {
+	case "", "snapshot", "wal":
+	default:
+		return fmt.Errorf("-to: unsupported format %q", opts.to)
+	}
+	if cmd == "replay" {
+		return replay(&opts, out)
+	}
+	return dispatchKey(cmd, &opts, out)
+}
+
+func dispatchKey(cmd string, opts *options, out io.Writer) error {
+	switch opts.keyType {
+	case "string":
+		return dispatchValue[string](cmd, opts, out)
+	case "int":
+		return dispatchValue[int](cmd, opts, out)
+	case "int64":
+		return dispatchValue[int64](cmd, opts, out)
+	case "uint64":
+		return dispatchValue[uint64](cmd, opts, out)
+	default:
+		return fmt.Errorf("unsupported key type %q", opts.keyType)
+	}
+}
+
+func dispatchValue[K comparable](cmd string, opts *options, out io.Writer) error {
+	switch opts.valueType {
+	case "string":
+		return execute[K, string](cmd, opts, out)
+	case "bytes":
+		return execute[K, []byte](cmd, opts, out)
+	case "int":
+		return execute[K, int](cmd, opts, out)
+	case "int64":
+		return execute[K, int64](cmd, opts, out)
+	case "float64":
+		return execute[K, float64](cmd, opts, out)
+	default:
+		return fmt.Errorf("unsupported value type %q", opts.valueType)
+	}
+}
+
+func execute[K comparable, V interface{}](cmd string, opts *options, out io.Writer) error {
+	switch cmd {
+	case "info":
+		return info[K, V](opts, out)
+	case "dump":
+		return dump[K, V](opts, out)
+	case "diff":
+		return diff[K, V](opts, out)
+	default:
+		return convert[K, V](opts)
+	}
+}
+
+// header 读取文件头，判断是快照还是预写日志
+func header(path string) (lru.SnapshotHeader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return lru.SnapshotHeader{}, err
+	}
+	defer file.Close()
+	return lru.ReadSnapshotHeader(file)
+}
+
+// read 读取快照，或加载预写日志旁边的快照后重放日志
+func read[K comparable, V interface{}](path string, key []byte) ([]lru.SnapshotEntry[K, V], error) {
+	h, err := header(path)
+	if err != nil {
+		return nil, err
+	}
+	var base []lru.SnapshotEntry[K, V]
+	if h.WAL {
+		if base, err = read[K, V](path+".snapshot", key); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if h.WAL {
+		return lru.ReplayWAL(file, key, base)
+	}
+	return lru.ReadSnapshot[K, V](file, key)
+}
+
+func info[K comparable, V interface{}](opts *options, out io.Writer) error {
+	path := opts.args[0]
+	h, err := header(path)
+	format := "snapshot"
+	if h.WAL {
+		format = "wal"
+	}
+	fmt.Fprintf(out, "format:    %s\nversion:   %d\nencrypted: %v\n", format, h.Version, h.Encrypted)
+	if err != nil {
+		return err
+	}
+
+	entries, err := read[K, V](path, opts.key)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	withTTL, expired, stale := 0, 0, 0
+	for _, e := range entries {
+		if !e.ExpireAt.IsZero() {
+			withTTL++
+			if !now.Before(e.ExpireAt) {
+				expired++
+			}
+		}
+		if e.Stale {
+			stale++
+		}
+	}
+	fmt.Fprintf(out, "entries:   %d\nwith ttl:  %d\nexpired:   %d\nstale:     %d\n", len(entries), withTTL, expired, stale)
+	return nil
+}
+
+func dump[K comparable, V interface{}](opts *options, out io.Writer) error {
+	entries, err := read[K, V](opts.args[0], opts.key)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		expire := "-"
+		if !e.ExpireAt.IsZero() {
+			expire = e.ExpireAt.Format(time.RFC3339)
+		}
+		if e.Stale {
+			fmt.Fprintf(out, "%v\t%v\t%s\tstale\n", e.Key, e.Value, expire)
+		} else {
+			fmt.Fprintf(out, "%v\t%v\t%s\n", e.Key, e.Value, expire)
+		}
+	}
+	return nil
+}
+
+func diff[K comparable, V interface{}](opts *options, out io.Writer) error {
+	oldEntries, err := read[K, V](opts.args[0], opts.key)
+	if err != nil {
+		return err
+	}
+	newEntries, err := read[K, V](opts.args[1], opts.key)
+	if err != nil {
+		return err
+	}
+
+	oldValues := make(map[K]V, len(oldEntries))
+	for _, e := range oldEntries {
+		oldValues[e.Key] = e.Value
+	}
+	for _, e := range newEntries {
+		oldValue, ok := oldValues[e.Key]
+		if !ok {
+			fmt.Fprintf(out, "+ %v\t%v\n", e.Key, e.Value)
+		} else if !reflect.DeepEqual(oldValue, e.Value) {
+			fmt.Fprintf(out, "~ %v\t%v -> %v\n", e.Key, oldValue, e.Value)
+		}
+		delete(oldValues, e.Key)
+	}
+	for _, e := range oldEntries {
+		if value, ok := oldValues[e.Key]; ok {
+			fmt.Fprintf(out, "- %v\t%v\n", e.Key, value)
+		}
+	}
+	return nil
+}
+
+func convert[K comparable, V interface{}](opts *options) error {
+	entries, err := read[K, V](opts.args[0], opts.key)
+	if err != nil {
+		return err
+	}
+	to := opts.to
+	if to == "" {
+		h, err := header(opts.args[0])
+		if err != nil {
+			return err
+		}
+		to = "snapshot"
+		if h.WAL {
+			to = "wal"
+		}
+	}
+	file, err := os.Create(opts.args[1])
+	if err != nil {
+		return err
+	}
+	if to == "wal" {
+		err = lru.WriteWAL(file, opts.outKey, entries)
+	} else {
+		err = lru.WriteSnapshot(file, opts.outKey, entries)
+	}
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func replay(opts *options, out io.Writer) error {
+	file, err := os.Open(opts.args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var records []bench.TraceRecord
+	switch opts.format {
+	case "arc":
+		records, err = bench.ParseARCTrace(file)
+	case "twitter":
+		records, err = bench.ParseTwitterTrace(file)
+	default:
+		return fmt.Errorf("-format: unsupported trace format %q", opts.format)
+	}
+	if err != nil {
+		return err
+	}
+	result := bench.Replay(lru.New[int, int](opts.size, nil, nil), records)
+	result.Name = filepath.Base(opts.args[0])
+	return bench.WriteReport(out, []bench.Result{result})
+}
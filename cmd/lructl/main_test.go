@@ -0,0 +1,139 @@
+//go:build !lru_minimal
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	lru "github.com/madokast/LRU"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "old")
+	newPath := filepath.Join(dir, "new")
+
+	cache := lru.New[string, int](10, nil, nil)
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	if err := cache.SaveToFile(oldPath); err != nil {
+		panic(err)
+	}
+	cache.Put("b", 3)
+	cache.Put("c", 4)
+	cache.Remove("a")
+	if err := cache.SaveToFile(newPath); err != nil {
+		panic(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"diff", "-v", "int", oldPath, newPath}, &out); err != nil {
+		panic(err)
+	}
+	t.Log(out.String())
+	if out.String() != "~ b\t2 -> 3\n+ c\t4\n- a\t1\n" {
+		panic(out.String())
+	}
+
+	key := strings.Repeat("01", 16)
+	encPath := filepath.Join(dir, "enc")
+	if err := run([]string{"convert", "-v", "int", "-out-key", key, newPath, encPath}, &out); err != nil {
+		panic(err)
+	}
+	out.Reset()
+	if err := run([]string{"info", "-v", "int", "-key", key, encPath}, &out); err != nil {
+		panic(err)
+	}
+	t.Log(out.String())
+	if !strings.Contains(out.String(), "encrypted: true") || !strings.Contains(out.String(), "entries:   2") {
+		panic(out.String())
+	}
+
+	if err := run([]string{"dump", "-v", "int", encPath}, &out); err == nil {
+		panic("dump without key")
+	}
+}
+
+func TestRun_WAL(t *testing.T) {
+	dir := t.TempDir()
+	walPath := filepath.Join(dir, "wal")
+	cache := lru.New[string, int](10, nil, nil)
+	if err := cache.OpenWAL(walPath, 0); err != nil {
+		panic(err)
+	}
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Invalidate("a")
+	cache.Remove("c")
+	if err := cache.CloseWAL(); err != nil {
+		panic(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"dump", "-v", "int", walPath}, &out); err != nil {
+		panic(err)
+	}
+	t.Log(out.String())
+	if out.String() != "a\t1\t-\tstale\nb\t2\t-\n" {
+		panic(out.String())
+	}
+
+	// 日志转换为快照，再转换回日志，失效标记不丢失
+	snapPath := filepath.Join(dir, "snap")
+	if err := run([]string{"convert", "-v", "int", "-to", "snapshot", walPath, snapPath}, &out); err != nil {
+		panic(err)
+	}
+	loaded := lru.New[string, int](10, nil, nil)
+	if err := loaded.LoadFromFile(snapPath); err != nil {
+		panic(err)
+	}
+	if _, stale, ok := loaded.GetStale("a"); !ok || !stale {
+		panic("stale lost in snapshot")
+	}
+	walPath2 := filepath.Join(dir, "wal2")
+	if err := run([]string{"convert", "-v", "int", "-to", "wal", snapPath, walPath2}, &out); err != nil {
+		panic(err)
+	}
+	recovered := lru.New[string, int](10, nil, nil)
+	if err := recovered.OpenWAL(walPath2, 0); err != nil {
+		panic(err)
+	}
+	defer recovered.CloseWAL()
+	if _, stale, ok := recovered.GetStale("a"); !ok || !stale {
+		panic("stale lost in WAL")
+	}
+	if value, ok := recovered.Get("b"); !ok || value != 2 || recovered.Number() != 2 {
+		panic(recovered.AllKeys())
+	}
+
+	out.Reset()
+	if err := run([]string{"info", "-v", "int", walPath2}, &out); err != nil {
+		panic(err)
+	}
+	if !strings.Contains(out.String(), "format:    wal") || !strings.Contains(out.String(), "stale:     1") {
+		panic(out.String())
+	}
+}
+
+func TestRun_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace")
+	if err := os.WriteFile(path, []byte("1 3 0 0\n1 3 0 1\n"), 0o644); err != nil {
+		panic(err)
+	}
+	var out bytes.Buffer
+	if err := run([]string{"replay", "-size", "10", path}, &out); err != nil {
+		panic(err)
+	}
+	t.Log(out.String())
+	if !strings.Contains(out.String(), "trace") || !strings.Contains(out.String(), "0.5000") {
+		panic(out.String())
+	}
+	if err := run([]string{"replay", "-format", "csv", path}, &out); err == nil {
+		panic("unsupported format")
+	}
+}
@@ -0,0 +1,131 @@
+package lru
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// WithDeadlineReaper PutUntil 写入的项到期时由后台 goroutine 主动移除，即使之后没有访问
+// goroutine 在第一次 PutUntil 时启动，持有缓存直到 Close，开启后必须调用 Close
+func WithDeadlineReaper[K comparable, V interface{}]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.reapDeadline = true
+	}
+}
+
+// PutUntil 写入 KV 对，在 deadline 时刻过期，适合令牌、租约等按绝对时间失效的缓存
+// 默认与 PutWithTTL 一样惰性删除；开启 WithDeadlineReaper 时在 deadline 到达时主动移除该项并执行失效函数，
+// 移除以 EventExpire 通知监听。之后再次写入同一个 key 时按新的写入决定过期时间。
+// deadline 不晚于当前时间时不写入，已有的 key 按过期移除。Close 之后不再主动移除，仍然惰性过期
+func (c *Cache[K, V]) PutUntil(key K, value V, deadline time.Time, opts ...PutOption) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !deadline.After(time.Now()) {
+		if c.checkKey(key) == nil {
+			c.removeUnlock(c.resolveUnlock(key), EventExpire)
+		}
+		return
+	}
+	if c.tryPutAtUnlock(key, value, 0, deadline, opts...) == nil && c.reapDeadline {
+		c.reapAtUnlock(c.resolveUnlock(key), deadline)
+	}
+}
+
+// deadlineReaper PutUntil 的后台移除。堆中的过期时间与缓存项当前的过期时间不一致时，说明该项已被重新写入，跳过
+type deadlineReaper[K comparable] struct {
+	heap warningHeap[K]
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// reapAtUnlock 安排在 deadline 移除 key
+func (c *Cache[K, V]) reapAtUnlock(key K, deadline time.Time) {
+	if c.reapClosed {
+		return
+	}
+	r := c.reaper
+	if r == nil {
+		r = &deadlineReaper[K]{
+			wake: make(chan struct{}, 1),
+			stop: make(chan struct{}),
+			done: make(chan struct{}),
+		}
+		c.reaper = r
+		go c.pprofDo(context.Background(), "deadline", func(context.Context) { c.reapLoop(r) })
+	}
+	if len(r.heap) > 2*len(c.m)+64 { // 反复写入的 key 留下大量旧的记录，重建
+		live := r.heap[:0]
+		for _, w := range r.heap {
+			if ele, ok := c.m[w.key]; ok && ele.Value.(*Entry[K, V]).expireAt.Equal(w.expireAt) {
+				live = append(live, w)
+			}
+		}
+		r.heap = live
+		heap.Init(&r.heap)
+	}
+	heap.Push(&r.heap, warning[K]{at: deadline, key: key, expireAt: deadline})
+	if r.heap[0].key == key {
+		select {
+		case r.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *Cache[K, V]) reapLoop(r *deadlineReaper[K]) {
+	defer close(r.done)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		c.lock.Lock()
+		next := c.reapUnlock(r, time.Now())
+		c.lock.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case <-r.stop:
+			return
+		case <-r.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// reapUnlock 移除到期的项，返回下一次到期的时间，没有时返回零值
+func (c *Cache[K, V]) reapUnlock(r *deadlineReaper[K], now time.Time) time.Time {
+	for len(r.heap) > 0 && !r.heap[0].at.After(now) {
+		w := heap.Pop(&r.heap).(warning[K])
+		if ele, ok := c.m[w.key]; ok && ele.Value.(*Entry[K, V]).expireAt.Equal(w.expireAt) {
+			c.removeElementUnlock(ele, EventExpire, true)
+		}
+	}
+	if len(r.heap) == 0 {
+		return time.Time{}
+	}
+	return r.heap[0].at
+}
+
+// stopReaper 停止 PutUntil 的后台移除，之后不再安排
+func (c *Cache[K, V]) stopReaper() {
+	c.lock.Lock()
+	r := c.reaper
+	c.reaper = nil
+	c.reapClosed = true
+	c.lock.Unlock()
+	if r != nil {
+		close(r.stop)
+		<-r.done
+	}
+}
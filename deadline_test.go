@@ -0,0 +1,81 @@
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_PutUntil(t *testing.T) {
+	var lock sync.Mutex
+	var expired []string
+	cache := New[string, int](10, func(key string, value int) {
+		lock.Lock()
+		expired = append(expired, key)
+		lock.Unlock()
+	}, nil, WithDeadlineReaper[string, int]())
+	defer cache.Close()
+	got := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string(nil), expired...)
+	}
+	waitFor := func(want []string) {
+		for deadline := time.Now().Add(time.Second); !reflect.DeepEqual(got(), want); time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				panic(got())
+			}
+		}
+	}
+
+	now := time.Now()
+	cache.PutUntil("a", 1, now.Add(10*time.Millisecond))
+	cache.PutUntil("b", 2, now.Add(time.Hour))
+	cache.PutUntil("renewed", 3, now.Add(10*time.Millisecond))
+	cache.Put("renewed", 3) // 重新写入后不再过期
+	cache.Put("past", 4)
+	cache.PutUntil("past", 4, now.Add(-time.Second))
+	if _, ok := cache.GetNoMove("past"); ok {
+		panic("past")
+	}
+
+	waitFor([]string{"past", "a"}) // 没有访问也被移除
+	cache.PutUntil("b", 2, time.Now().Add(10*time.Millisecond))
+	waitFor([]string{"past", "a", "b"})
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"renewed"}) {
+		panic(keys)
+	}
+
+	// Close 之后仍然惰性过期
+	cache.Close()
+	at := time.Now().Add(time.Millisecond)
+	cache.PutUntil("c", 5, at)
+	for time.Now().Before(at) {
+		time.Sleep(time.Millisecond)
+	}
+	if cache.Number() != 2 {
+		panic(cache.AllKeys())
+	}
+	if _, ok := cache.Get("c"); ok {
+		panic("c")
+	}
+}
+
+func TestCache_PutUntil_Lazy(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+	at := time.Now().Add(time.Millisecond)
+	cache.PutUntil("a", 1, at)
+	if cache.reaper != nil {
+		panic("reaper started without WithDeadlineReaper")
+	}
+	for time.Now().Before(at) {
+		time.Sleep(time.Millisecond)
+	}
+	if cache.Number() != 1 {
+		panic(cache.AllKeys()) // 没有后台移除，惰性过期
+	}
+	if _, ok := cache.Get("a"); ok {
+		panic("a")
+	}
+}
@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithFrequencyDecay 每隔 interval 将所有缓存项的命中次数减半，使流量转移后曾经的热点 key 不会一直保持高计数
+// 命中次数用于 ExportRecords 等按访问频率分析的场景。衰减是惰性的，在周期结束后的第一次 Get 或写入时进行，
+// 已衰减的周期数见 Stats().DecayEpoch
+func WithFrequencyDecay[K comparable, V interface{}](interval time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.decayInterval = interval
+	}
+}
+
+// decayState 命中次数衰减的状态
+type decayState struct {
+	next  time.Time // 下一次衰减的时间
+	epoch uint64    // 已衰减的周期数
+}
+
+// decayUnlock 到期时将命中次数按经过的周期数减半
+func (c *Cache[K, V]) decayUnlock() {
+	if c.decayInterval <= 0 {
+		return
+	}
+	now := time.Now()
+	if c.decay.next.IsZero() {
+		c.decay.next = now.Add(c.decayInterval)
+		return
+	}
+	if now.Before(c.decay.next) {
+		return
+	}
+	periods := uint64(now.Sub(c.decay.next)/c.decayInterval) + 1
+	shift := periods
+	if shift > 64 {
+		shift = 64
+	}
+	for ele := c.li.Front(); ele != nil; ele = ele.Next() {
+		e := ele.Value.(*Entry[K, V])
+		atomic.StoreUint64(&e.hits, atomic.LoadUint64(&e.hits)>>shift)
+	}
+	c.decay.epoch += periods
+	c.decay.next = c.decay.next.Add(time.Duration(periods) * c.decayInterval)
+}
+
+// Hits 返回命中次数，开启 WithFrequencyDecay 时为衰减后的值
+func (e *Entry[K, V]) Hits() uint64 {
+	return atomic.LoadUint64(&e.hits)
+}
@@ -0,0 +1,30 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_WithFrequencyDecay(t *testing.T) {
+	cache := New[int, int](10, nil, nil, WithFrequencyDecay[int, int](50*time.Millisecond))
+	cache.Put(1, 1)
+	for i := 0; i < 8; i++ {
+		cache.Get(1)
+	}
+	ele, _ := cache.LeastRecentlyUsed()
+	if ele.Hits() != 8 {
+		panic(ele.Hits())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	cache.Get(2) // 触发衰减
+	if ele.Hits() != 4 || cache.Stats().DecayEpoch != 1 {
+		panic(ele.Hits())
+	}
+
+	time.Sleep(100 * time.Millisecond) // 经过两个周期
+	cache.Put(2, 2)
+	if ele.Hits() != 1 || cache.Stats().DecayEpoch != 3 {
+		panic(cache.Stats().DecayEpoch)
+	}
+}
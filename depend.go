@@ -0,0 +1,86 @@
+package lru
+
+// AddDependency 声明 child 依赖于 parent，parent 被移除、淘汰或过期时 child 随之被移除（执行失效函数），并且可以传递
+// 适用于由其他缓存项计算得到的派生数据。child 或 parent 不存在时返回 false。
+// 依赖关系只保存在内存中，不写入快照和预写日志
+func (c *Cache[K, V]) AddDependency(child, parent K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	child, parent = c.resolveUnlock(child), c.resolveUnlock(parent)
+	if _, ok := c.m[child]; !ok {
+		return false
+	}
+	if _, ok := c.m[parent]; !ok {
+		return false
+	}
+	if c.dependents == nil {
+		c.dependents = map[K]map[K]struct{}{}
+		c.dependsOn = map[K]map[K]struct{}{}
+	}
+	addEdge(c.dependents, parent, child)
+	addEdge(c.dependsOn, child, parent)
+	return true
+}
+
+// RemoveDependency 取消 child 对 parent 的依赖
+func (c *Cache[K, V]) RemoveDependency(child, parent K) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	child, parent = c.resolveUnlock(child), c.resolveUnlock(parent)
+	removeEdge(c.dependents, parent, child)
+	removeEdge(c.dependsOn, child, parent)
+}
+
+// Dependents 返回直接依赖于 parent 的 key
+func (c *Cache[K, V]) Dependents(parent K) []K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	parent = c.resolveUnlock(parent)
+	keys := make([]K, 0, len(c.dependents[parent]))
+	for child := range c.dependents[parent] {
+		keys = append(keys, child)
+	}
+	return keys
+}
+
+// cascadeUnlock key 已被移除，清理它的依赖关系并移除依赖它的项
+func (c *Cache[K, V]) cascadeUnlock(key K, expire bool) {
+	if c.dependents == nil {
+		return
+	}
+	for parent := range c.dependsOn[key] {
+		removeEdge(c.dependents, parent, key)
+	}
+	delete(c.dependsOn, key)
+
+	children := c.dependents[key]
+	delete(c.dependents, key)
+	for child := range children {
+		if expire {
+			c.removeUnlock(child, EventRemove)
+		} else {
+			c.removeNoExpireUnlock(child)
+		}
+	}
+}
+
+func addEdge[K comparable](edges map[K]map[K]struct{}, from, to K) {
+	set, ok := edges[from]
+	if !ok {
+		set = map[K]struct{}{}
+		edges[from] = set
+	}
+	set[to] = struct{}{}
+}
+
+func removeEdge[K comparable](edges map[K]map[K]struct{}, from, to K) {
+	set, ok := edges[from]
+	if !ok {
+		return
+	}
+	delete(set, to)
+	if len(set) == 0 {
+		delete(edges, from)
+	}
+}
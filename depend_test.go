@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCache_AddDependency(t *testing.T) {
+	var expired []string
+	cache := New[string, int](10, func(key string, value int) { expired = append(expired, key) }, nil)
+	for _, key := range []string{"table", "view", "report", "other"} {
+		cache.Put(key, 0)
+	}
+	if !cache.AddDependency("view", "table") || !cache.AddDependency("report", "view") {
+		panic("AddDependency")
+	}
+	if cache.AddDependency("view", "missing") {
+		panic("missing parent")
+	}
+
+	cache.Remove("table")
+	sort.Strings(expired)
+	if !reflect.DeepEqual(expired, []string{"report", "table", "view"}) {
+		panic(expired)
+	}
+	if !reflect.DeepEqual(cache.AllKeys(), []string{"other"}) {
+		panic(cache.AllKeys())
+	}
+	if len(cache.dependents) != 0 || len(cache.dependsOn) != 0 {
+		panic(cache.dependents)
+	}
+}
+
+func TestCache_AddDependency_Evict(t *testing.T) {
+	cache := New[int, int](3, nil, nil)
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3)
+	cache.AddDependency(3, 1)
+	cache.AddDependency(1, 3) // 循环依赖
+	cache.RemoveIf(func(k int) bool { return k != 2 })
+	if !reflect.DeepEqual(cache.AllKeys(), []int{2}) {
+		panic(cache.AllKeys())
+	}
+
+	cache.Put(4, 4)
+	cache.Put(5, 5)
+	cache.AddDependency(5, 2)
+	cache.Put(6, 6) // 淘汰 2，级联移除 5
+	if !reflect.DeepEqual(cache.AllKeys(), []int{6, 4}) {
+		panic(cache.AllKeys())
+	}
+}
+
+func TestCache_AddDependency_Alias(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+	cache.Put("table", 0)
+	cache.Put("view", 0)
+	cache.Alias("t", "table")
+	cache.Alias("v", "view")
+	if !cache.AddDependency("v", "t") {
+		panic("AddDependency")
+	}
+	if !reflect.DeepEqual(cache.Dependents("t"), []string{"view"}) {
+		panic(cache.Dependents("t"))
+	}
+	cache.Remove("table")
+	if cache.Number() != 0 {
+		panic(cache.AllKeys())
+	}
+}
@@ -0,0 +1,301 @@
+// Package diskcache 基于单个追加写日志文件的磁盘缓存，实现 lru.Cacher[string, []byte]
+// 文件中依次存放 put/remove 记录，内存中只保留 key 到文件偏移的 LRU 索引，打开时重放日志重建索引。
+// 失效记录占用的空间在其超过存活数据时通过压缩回收。
+// Get 只在内存索引中把 key 移到最前，不写日志，访问先后不持久化：重新打开后按最后一次写入的先后恢复，
+// 只有压缩时按当时的访问先后重写文件
+package diskcache
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	lru "github.com/madokast/LRU"
+)
+
+const (
+	opPut    byte = 1
+	opRemove byte = 2
+
+	headerSize     = 9       // op(1) + keyLen(4) + valueLen(4)
+	minCompactSize = 1 << 20 // 文件小于该值时不自动压缩
+	maxRecordSize  = 1 << 30 // key 和 value 的长度之和的上限
+)
+
+var (
+	// ErrCorrupted 文件损坏：记录的操作类型或长度非法
+	ErrCorrupted = errors.New("diskcache: corrupted file")
+	// ErrTooLarge key 和 value 的长度之和超过 1GB
+	ErrTooLarge = errors.New("diskcache: record too large")
+)
+
+// record 一条 put 记录在文件中的位置
+type record struct {
+	offset int64 // value 的偏移
+	length int   // value 的长度
+	size   int   // 整条记录的长度
+}
+
+// Cache 磁盘缓存，并发安全
+type Cache struct {
+	lock      sync.Mutex
+	path      string
+	file      *os.File
+	fileSize  int64
+	deadSize  int64 // 失效记录占用的文件大小
+	index     *lru.Cache[string, record]
+	replaying bool
+	err       error // 第一个写入错误
+}
+
+var _ lru.Cacher[string, []byte] = (*Cache)(nil)
+
+// Open 打开或创建磁盘缓存
+// maxSize 最大缓存大小，为所有 key 和 value 的字节数之和，超出时淘汰最近最少使用的项
+// 文件尾部不完整的记录（如写入时进程崩溃）会被截断，记录的操作类型或长度非法时返回 ErrCorrupted
+func Open(path string, maxSize int) (*Cache, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cache{path: path, file: file}
+	c.index = lru.New[string, record](maxSize, c.onExpire, func(key string, r record) int {
+		return len(key) + r.length
+	})
+	if err = c.replay(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// replay 重放日志重建索引
+func (c *Cache) replay() error {
+	c.replaying = true
+	defer func() { c.replaying = false }()
+
+	info, err := c.file.Stat()
+	if err != nil {
+		return err
+	}
+	reader := bufio.NewReader(io.NewSectionReader(c.file, 0, 1<<62))
+	header := make([]byte, headerSize)
+	var offset int64
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			break
+		}
+		keyLen := int64(binary.BigEndian.Uint32(header[1:5]))
+		valueLen := int64(binary.BigEndian.Uint32(header[5:9]))
+		if keyLen+valueLen > maxRecordSize {
+			return fmt.Errorf("%w: record length %d at offset %d", ErrCorrupted, keyLen+valueLen, offset)
+		}
+		if offset+headerSize+keyLen+valueLen > info.Size() { // 写入时崩溃留下的不完整记录
+			break
+		}
+		key := make([]byte, keyLen)
+		if _, err := io.ReadFull(reader, key); err != nil {
+			break
+		}
+		if _, err := reader.Discard(int(valueLen)); err != nil {
+			break
+		}
+		size := headerSize + int(keyLen+valueLen)
+		switch header[0] {
+		case opPut:
+			if old, ok := c.index.GetNoMove(string(key)); ok {
+				c.deadSize += int64(old.size)
+			}
+			c.index.Put(string(key), record{offset: offset + headerSize + keyLen, length: int(valueLen), size: size})
+		case opRemove:
+			c.deadSize += int64(size)
+			c.index.Remove(string(key))
+		default:
+			return fmt.Errorf("%w: unknown op %d at offset %d", ErrCorrupted, header[0], offset)
+		}
+		offset += int64(size)
+	}
+
+	c.fileSize = offset
+	return c.file.Truncate(offset)
+}
+
+// onExpire 索引项被移除或淘汰时，记录失效空间并追加 remove 记录
+func (c *Cache) onExpire(key string, r record) {
+	c.deadSize += int64(r.size)
+	if c.replaying {
+		return
+	}
+	if _, err := c.append(opRemove, key, nil); err != nil {
+		c.setErr(err)
+	}
+}
+
+func (c *Cache) append(op byte, key string, value []byte) (record, error) {
+	if len(key)+len(value) > maxRecordSize {
+		return record{}, fmt.Errorf("%w: %d bytes", ErrTooLarge, len(key)+len(value))
+	}
+	buf := make([]byte, headerSize+len(key)+len(value))
+	buf[0] = op
+	binary.BigEndian.PutUint32(buf[1:5], uint32(len(key)))
+	binary.BigEndian.PutUint32(buf[5:9], uint32(len(value)))
+	copy(buf[headerSize:], key)
+	copy(buf[headerSize+len(key):], value)
+	if _, err := c.file.WriteAt(buf, c.fileSize); err != nil {
+		return record{}, err
+	}
+	r := record{offset: c.fileSize + int64(headerSize+len(key)), length: len(value), size: len(buf)}
+	c.fileSize += int64(len(buf))
+	return r, nil
+}
+
+func (c *Cache) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// Write 写入 KV 对，返回写文件的错误
+func (c *Cache) Write(key string, value []byte) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	old, exist := c.index.GetNoMove(key)
+	r, err := c.append(opPut, key, value)
+	if err != nil {
+		return err
+	}
+	if exist {
+		c.deadSize += int64(old.size)
+	}
+	c.index.Put(key, r)
+	return c.maybeCompact()
+}
+
+// Put 写入 KV 对，写文件的错误通过 Err 获取
+func (c *Cache) Put(key string, value []byte) {
+	if err := c.Write(key, value); err != nil {
+		c.lock.Lock()
+		c.setErr(err)
+		c.lock.Unlock()
+	}
+}
+
+// Get 读取 key 对应的 value，读文件失败时返回 false，错误通过 Err 获取。访问先后不写入文件
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	r, ok := c.index.Get(key)
+	if !ok {
+		return nil, false
+	}
+	value := make([]byte, r.length)
+	if _, err := c.file.ReadAt(value, r.offset); err != nil {
+		c.setErr(err)
+		return nil, false
+	}
+	return value, true
+}
+
+// Remove 移除 KV 对
+func (c *Cache) Remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.index.Remove(key)
+}
+
+// Size 返回所有 key 和 value 的字节数之和
+func (c *Cache) Size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.index.Size()
+}
+
+// Number 返回元素个数
+func (c *Cache) Number() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.index.Number()
+}
+
+// FileSize 返回日志文件大小
+func (c *Cache) FileSize() int64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.fileSize
+}
+
+// Err 返回 Put/Get/Remove 过程中遇到的第一个文件错误
+func (c *Cache) Err() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.err
+}
+
+// Compact 将存活的记录按访问先后重写到新文件，回收失效记录占用的空间
+func (c *Cache) Compact() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.compact()
+}
+
+func (c *Cache) maybeCompact() error {
+	if c.fileSize < minCompactSize || c.deadSize*2 < c.fileSize {
+		return nil
+	}
+	return c.compact()
+}
+
+func (c *Cache) compact() error {
+	tmpPath := c.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	compacted := &Cache{path: c.path, file: tmp}
+
+	// 从最久未访问的开始写，重放时访问先后不变
+	keys := c.index.AllKeys()
+	records := make([]record, len(keys))
+	for i := len(keys) - 1; i >= 0; i-- {
+		r, _ := c.index.GetNoMove(keys[i])
+		value := make([]byte, r.length)
+		if _, err = c.file.ReadAt(value, r.offset); err == nil {
+			records[i], err = compacted.append(opPut, keys[i], value)
+		}
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmpPath)
+			return err
+		}
+	}
+	if err = tmp.Sync(); err == nil {
+		err = os.Rename(tmpPath, c.path)
+	}
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	_ = c.file.Close()
+	c.file = tmp
+	c.fileSize = compacted.fileSize
+	c.deadSize = 0
+	for i := len(keys) - 1; i >= 0; i-- {
+		c.index.Put(keys[i], records[i])
+	}
+	return nil
+}
+
+// Close 关闭文件
+func (c *Cache) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.file.Close()
+}
@@ -0,0 +1,152 @@
+package diskcache
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestCache_Reopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	c, err := Open(path, 100)
+	if err != nil {
+		panic(err)
+	}
+	c.Put("a", []byte("1"))
+	c.Put("b", []byte("2"))
+	c.Put("a", []byte("3"))
+	c.Remove("b")
+	if err = c.Close(); err != nil {
+		panic(err)
+	}
+
+	c, err = Open(path, 100)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	value, ok := c.Get("a")
+	if !ok || string(value) != "3" {
+		panic(string(value))
+	}
+	if _, ok = c.Get("b"); ok {
+		panic("b removed")
+	}
+	if c.Err() != nil {
+		panic(c.Err())
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	c, err := Open(filepath.Join(t.TempDir(), "cache"), 20)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	for i := 0; i < 10; i++ {
+		c.Put(strconv.Itoa(i), []byte("0123"))
+	}
+	if c.Number() != 4 || c.Size() != 20 {
+		panic(c.Number())
+	}
+	if _, ok := c.Get("0"); ok {
+		panic("0 evicted")
+	}
+}
+
+func TestCache_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	c, err := Open(path, 1000)
+	if err != nil {
+		panic(err)
+	}
+	for i := 0; i < 100; i++ {
+		c.Put(strconv.Itoa(i%3), []byte(strconv.Itoa(i)))
+	}
+	_, _ = c.Get("0")
+	before := c.FileSize()
+	if err = c.Compact(); err != nil {
+		panic(err)
+	}
+	if c.FileSize() >= before {
+		panic(c.FileSize())
+	}
+	if err = c.Close(); err != nil {
+		panic(err)
+	}
+
+	c, err = Open(path, 1000)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	if c.Number() != 3 {
+		panic(c.Number())
+	}
+	value, _ := c.Get("1")
+	if string(value) != "97" {
+		panic(string(value))
+	}
+	if keys := c.index.AllKeys(); keys[2] != "2" {
+		panic(keys)
+	}
+}
+
+func TestCache_Truncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+	c, err := Open(path, 100)
+	if err != nil {
+		panic(err)
+	}
+	c.Put("a", []byte("1"))
+	c.Put("b", []byte("2"))
+	_ = c.Close()
+
+	info, _ := os.Stat(path)
+	if err = os.Truncate(path, info.Size()-1); err != nil {
+		panic(err)
+	}
+
+	c, err = Open(path, 100)
+	if err != nil {
+		panic(err)
+	}
+	defer c.Close()
+	if c.Number() != 1 {
+		panic(c.Number())
+	}
+	c.Put("c", []byte("3"))
+	value, ok := c.Get("c")
+	if !ok || string(value) != "3" {
+		panic(string(value))
+	}
+}
+
+func TestCache_Corrupted(t *testing.T) {
+	dir := t.TempDir()
+	open := func(name string, data []byte) error {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			panic(err)
+		}
+		c, err := Open(path, 100)
+		if err == nil {
+			_ = c.Close()
+		}
+		return err
+	}
+
+	// 长度超过上限，不按长度分配内存
+	if err := open("huge", []byte{opPut, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 1, 'k'}); !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+	if err := open("op", []byte{9, 0, 0, 0, 1, 0, 0, 0, 1, 'k', 'v'}); !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+	// 长度超过文件剩余大小视为写入时崩溃，截断
+	if err := open("tail", []byte{opPut, 0, 0, 0, 1, 0x20, 0, 0, 0, 'k'}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,121 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDoPanicked fn 发生 panic 时，同一 key 上等待的其他 Do 调用得到该错误
+var ErrDoPanicked = errors.New("lru: Do function panicked")
+
+// call 一次正在进行的加载
+type call[V interface{}] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// Do 获取 key 对应的 value，未命中时调用 fn 加载并以 ttl 写入缓存
+// 同一 key 的并发调用只会执行一次 fn，其余调用等待并共享结果。
+// fn 返回错误时不写入缓存，若 ttl > 0 则错误本身被缓存 ttl 时长，期间对该 key 的 Do 直接返回该错误，避免反复请求失败的数据源。
+// ttl <= 0 时 value 永不过期，且不缓存错误。开启 WithRefreshAhead 时临近过期的命中会触发后台刷新，
+// opts 设置 SoftTTL 时超过软过期的命中也会触发后台刷新。开启 WithCircuitBreaker 时加载函数连续失败后熔断
+func (c *Cache[K, V]) Do(key K, fn func() (V, error), ttl time.Duration, opts ...PutOption) (V, error) {
+	return c.do(context.Background(), key, func(context.Context) (V, error) { return fn() }, ttl, opts)
+}
+
+// DoContext 同 Do，ctx 传给 fn 和 WithValidator 的校验函数。开启 WithPprofLabels 时传给 fn 的 ctx 带有 pprof 标签
+func (c *Cache[K, V]) DoContext(ctx context.Context, key K, fn func(ctx context.Context) (V, error), ttl time.Duration, opts ...PutOption) (V, error) {
+	return c.do(ctx, key, fn, ttl, opts)
+}
+
+func (c *Cache[K, V]) do(ctx context.Context, key K, fn func(ctx context.Context) (V, error), ttl time.Duration, opts []PutOption) (V, error) {
+	key = c.resolve(key) // 别名与 primary 共享同一次加载
+	value, ok, stale := c.getForDo(key)
+	if ok {
+		c.refresh(key, func() (V, error) { return fn(ctx) }, ttl, opts)
+		return value, nil
+	}
+
+	c.flightLock.Lock()
+	if f, ok := c.flights[key]; ok {
+		c.flightLock.Unlock()
+		f.wg.Wait()
+		return f.value, f.err
+	}
+	if value, ok := c.peek(key); ok { // 可能在等待 flightLock 期间已被其他 Do 加载
+		c.flightLock.Unlock()
+		return value, nil
+	}
+	if c.negatives != nil {
+		if err, ok := c.negatives.Get(key); ok {
+			c.flightLock.Unlock()
+			var zero V
+			return zero, err
+		}
+	}
+	if limit := int(c.inflightLimit.Load()); limit > 0 && len(c.flights) >= limit {
+		c.flightLock.Unlock()
+		if stale != nil {
+			return stale.value, nil
+		}
+		var zero V
+		return zero, &InflightLimitError{Limit: limit}
+	}
+	if !c.breaker.allow() {
+		c.flightLock.Unlock()
+		if stale != nil {
+			return stale.value, nil
+		}
+		var zero V
+		return zero, ErrCircuitOpen
+	}
+	if c.flights == nil {
+		c.flights = map[K]*call[V]{}
+	}
+	f := &call[V]{err: ErrDoPanicked}
+	f.wg.Add(1)
+	c.flights[key] = f
+	c.flightLock.Unlock()
+
+	defer func() {
+		c.breaker.report(f.err)
+		c.flightLock.Lock()
+		delete(c.flights, key)
+		c.flightLock.Unlock()
+		f.wg.Done()
+	}()
+
+	if c.revalidate(ctx, key, stale, ttl) {
+		f.value, f.err = stale.value, nil
+		return f.value, f.err
+	}
+	c.timeLoad(ctx, "load", func(ctx context.Context) error {
+		f.value, f.err = fn(ctx)
+		return f.err
+	})
+	if f.err == nil {
+		c.PutWithTTL(key, f.value, ttl, opts...)
+	} else if ttl > 0 {
+		c.flightLock.Lock()
+		if c.negatives == nil {
+			c.negatives = New[K, error](c.maxSize, nil, nil)
+		}
+		c.flightLock.Unlock()
+		c.negatives.PutWithTTL(key, f.err, ttl)
+	}
+	return f.value, f.err
+}
+
+// peek 查找未过期且未失效的 value，不修改访问顺序和统计信息
+func (c *Cache[K, V]) peek(key K) (value V, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	ele, ok := c.m[c.resolveUnlock(key)]
+	if !ok || ele.Value.(*Entry[K, V]).stale || ele.Value.(*Entry[K, V]).expired(time.Now()) {
+		return value, false
+	}
+	return ele.Value.(*Entry[K, V]).value, true
+}
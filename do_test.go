@@ -0,0 +1,95 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_Do(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	var calls atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := cache.Do(1, func() (int, error) {
+				calls.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return 100, nil
+			}, time.Minute)
+			if err != nil || value != 100 {
+				panic(value)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		panic(calls.Load())
+	}
+}
+
+func TestCache_Do2(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	errLoad := errors.New("load")
+	calls := 0
+	fn := func() (int, error) {
+		calls++
+		return 0, errLoad
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Do(1, fn, 20*time.Millisecond); err != errLoad {
+			panic(err)
+		}
+	}
+	if calls != 1 {
+		panic(calls)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cache.Do(1, fn, 20*time.Millisecond); err != errLoad {
+		panic(err)
+	}
+	if calls != 2 {
+		panic(calls)
+	}
+}
+
+func TestCache_Do_Alias(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+	cache.Put("p", 1)
+	cache.Alias("a", "p")
+	cache.Invalidate("p")
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Do("a", func() (int, error) {
+			<-release
+			return 2, nil
+		}, time.Minute)
+	}()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		cache.flightLock.Lock()
+		n := len(cache.flights)
+		cache.flightLock.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			panic("flight not started")
+		}
+	}
+
+	// 通过 primary 调用时等待别名上进行中的加载，而不是再次加载
+	time.AfterFunc(time.Millisecond, func() { close(release) })
+	if value, err := cache.Do("p", func() (int, error) { return 3, nil }, time.Minute); value != 2 || err != nil {
+		panic(value)
+	}
+	<-done
+}
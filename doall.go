@@ -0,0 +1,129 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMissingKey DoAll 的批量加载函数没有返回某个 key 时，等待该 key 的其他 Do 调用得到该错误
+var ErrMissingKey = errors.New("lru: key missing from batch load")
+
+// DoAll 批量获取 keys，未命中的 key 一次性交给 fn 加载并以 ttl 写入缓存，返回找到的 KV
+// 与 Do 共享同一组正在进行的加载：已由其他 Do 或 DoAll 加载中的 key 等待其结果，
+// 本次加载中的 key 上的 Do 调用也会等待本次 fn 的结果。fn 没有返回的 key 不写入缓存，也不出现在结果中。
+// fn 或等待的加载返回错误时，返回已得到的结果和第一个错误。DoAll 不缓存错误，也不受熔断器限制
+func (c *Cache[K, V]) DoAll(keys []K, fn func(keys []K) (map[K]V, error), ttl time.Duration, opts ...PutOption) (map[K]V, error) {
+	results := make(map[K]V, len(keys))
+	var missing []K
+	for _, key := range keys {
+		if _, ok := results[key]; ok {
+			continue
+		}
+		if value, ok := c.Get(key); ok {
+			results[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	waits := map[K]*call[V]{}
+	owns := map[K]*call[V]{}
+	c.flightLock.Lock()
+	for _, key := range missing {
+		if _, ok := waits[key]; ok {
+			continue
+		}
+		if _, ok := owns[key]; ok {
+			continue
+		}
+		if f, ok := c.flights[key]; ok {
+			waits[key] = f
+		} else if value, ok := c.peek(key); ok {
+			results[key] = value
+		} else {
+			owns[key] = &call[V]{err: ErrDoPanicked}
+		}
+	}
+	if limit := int(c.inflightLimit.Load()); limit > 0 && len(owns) > 0 && len(c.flights)+len(owns) > limit {
+		c.flightLock.Unlock()
+		return results, &InflightLimitError{Limit: limit}
+	}
+	if c.flights == nil {
+		c.flights = map[K]*call[V]{}
+	}
+	for key, f := range owns {
+		f.wg.Add(1)
+		c.flights[key] = f
+	}
+	c.flightLock.Unlock()
+
+	var firstErr error
+	if len(owns) > 0 {
+		firstErr = c.loadAll(owns, fn, ttl, opts)
+		for key, f := range owns {
+			if f.err == nil {
+				results[key] = f.value
+			}
+		}
+	}
+	for key, f := range waits {
+		f.wg.Wait()
+		if f.err == nil {
+			results[key] = f.value
+		} else if firstErr == nil && !errors.Is(f.err, ErrMissingKey) {
+			firstErr = f.err
+		}
+	}
+	return results, firstErr
+}
+
+// loadAll 调用 fn 加载 owns 中的 key，写入缓存并完成对应的加载
+func (c *Cache[K, V]) loadAll(owns map[K]*call[V], fn func(keys []K) (map[K]V, error), ttl time.Duration, opts []PutOption) error {
+	keys := make([]K, 0, len(owns))
+	for key := range owns {
+		keys = append(keys, key)
+	}
+	defer func() {
+		c.flightLock.Lock()
+		for key := range owns {
+			delete(c.flights, key)
+		}
+		c.flightLock.Unlock()
+		for _, f := range owns {
+			f.wg.Done()
+		}
+	}()
+
+	var loaded map[K]V
+	var err error
+	c.timeLoad(context.Background(), "load_all", func(context.Context) error {
+		loaded, err = fn(keys)
+		return err
+	})
+	for key, f := range owns {
+		if err != nil {
+			f.err = err
+		} else if value, ok := loaded[key]; ok {
+			f.value, f.err = value, nil
+			c.PutWithTTL(key, value, ttl, opts...)
+		} else {
+			f.err = ErrMissingKey
+		}
+	}
+	return err
+}
+
+// InflightLoads 返回 Do 和 DoAll 正在加载的 key，用于调试
+func (c *Cache[K, V]) InflightLoads() []K {
+	c.flightLock.Lock()
+	defer c.flightLock.Unlock()
+	keys := make([]K, 0, len(c.flights))
+	for key := range c.flights {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,84 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCache_DoAll(t *testing.T) {
+	cache := New[int, int](100, nil, nil)
+	cache.Put(1, 10)
+
+	var batches [][]int
+	fn := func(keys []int) (map[int]int, error) {
+		sort.Ints(keys)
+		batches = append(batches, keys)
+		loaded := map[int]int{}
+		for _, key := range keys {
+			if key != 4 { // 4 不存在
+				loaded[key] = key * 10
+			}
+		}
+		return loaded, nil
+	}
+	results, err := cache.DoAll([]int{1, 2, 3, 3, 4}, fn, 0)
+	if err != nil {
+		panic(err)
+	}
+	if !reflect.DeepEqual(results, map[int]int{1: 10, 2: 20, 3: 30}) {
+		panic(results)
+	}
+	if !reflect.DeepEqual(batches, [][]int{{2, 3, 4}}) {
+		panic(batches)
+	}
+	if value, _ := cache.Get(3); value != 30 {
+		panic(value)
+	}
+
+	errLoad := errors.New("load failed")
+	results, err = cache.DoAll([]int{1, 5}, func(keys []int) (map[int]int, error) { return nil, errLoad }, 0)
+	if !errors.Is(err, errLoad) || len(results) != 1 {
+		panic(err)
+	}
+}
+
+func TestCache_DoAllCoalescing(t *testing.T) {
+	cache := New[int, int](100, nil, nil)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan map[int]int)
+	go func() {
+		results, _ := cache.DoAll([]int{1, 2}, func(keys []int) (map[int]int, error) {
+			close(started)
+			<-release
+			return map[int]int{1: 1, 2: 2}, nil
+		}, 0)
+		done <- results
+	}()
+	<-started
+
+	keys := cache.InflightLoads()
+	sort.Ints(keys)
+	if !reflect.DeepEqual(keys, []int{1, 2}) {
+		panic(keys)
+	}
+
+	// 单 key 的 Do 等待批量加载的结果
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	value, err := cache.Do(2, func() (int, error) { panic("should wait for DoAll") }, 0)
+	if value != 2 || err != nil {
+		panic(err)
+	}
+	if results := <-done; len(results) != 2 {
+		panic(results)
+	}
+	if len(cache.InflightLoads()) != 0 {
+		panic(cache.InflightLoads())
+	}
+}
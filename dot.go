@@ -0,0 +1,94 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteDOT 以 Graphviz DOT 格式输出缓存状态，可用 dot -Tsvg 渲染
+// 访问链表从最近访问到最久未访问排成一行，每个节点标注 key、大小和写入至今的时长，失效或已过期的项以虚线表示。
+// 开启 WithAdaptiveCapacity 或 WithShadow 时，幽灵列表和影子缓存作为单独的子图输出。输出期间持有读锁
+func (c *Cache[K, V]) WriteDOT(w io.Writer) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	now := time.Now()
+	fmt.Fprintln(bw, "digraph lru {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [shape=record];")
+
+	fmt.Fprintln(bw, "\tsubgraph cluster_recency {")
+	fmt.Fprintf(bw, "\t\tlabel=%s;\n", strconv.Quote(fmt.Sprintf("recency size=%d/%d number=%d", c.curSize, c.maxSize, len(c.m))))
+	i := 0
+	for ele := c.li.Front(); ele != nil; ele = ele.Next() {
+		e := ele.Value.(*Entry[K, V])
+		label := dotRecord(fmt.Sprint(e.key), fmt.Sprintf("size %d", c.sizeOf(e.key, e.value)), fmt.Sprintf("age %v", now.Sub(e.createTime).Round(time.Millisecond)))
+		style := ""
+		if e.stale || e.expired(now) {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(bw, "\t\te%d [label=%s%s];\n", i, label, style)
+		if i > 0 {
+			fmt.Fprintf(bw, "\t\te%d -> e%d;\n", i-1, i)
+		}
+		i++
+	}
+	fmt.Fprintln(bw, "\t}")
+
+	if c.adapt.ghost != nil {
+		writeGhostDOT(bw, "ghost", c.adapt.ghost)
+	}
+	if c.shadow.cache != nil {
+		writeGhostDOT(bw, "shadow", c.shadow.cache)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeGhostDOT 输出只记录 key 和大小的列表
+func writeGhostDOT[K comparable](w io.Writer, name string, g *ghostList[K]) {
+	fmt.Fprintf(w, "\tsubgraph cluster_%s {\n", name)
+	fmt.Fprintf(w, "\t\tlabel=%s;\n", strconv.Quote(fmt.Sprintf("%s size=%d/%d", name, g.size, g.maxSize)))
+	fmt.Fprintln(w, "\t\tnode [style=dotted];")
+	i := 0
+	for ele := g.li.Front(); ele != nil; ele = ele.Next() {
+		ge := ele.Value.(ghostEntry[K])
+		fmt.Fprintf(w, "\t\t%s%d [label=%s];\n", name, i, dotRecord(fmt.Sprint(ge.key), fmt.Sprintf("size %d", ge.size)))
+		if i > 0 {
+			fmt.Fprintf(w, "\t\t%s%d -> %s%d;\n", name, i-1, name, i)
+		}
+		i++
+	}
+	fmt.Fprintln(w, "\t}")
+}
+
+// dotRecord 拼接 record 形状的标签，转义每个字段中的特殊字符
+func dotRecord(fields ...string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i, field := range fields {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+		for _, r := range field {
+			switch r {
+			case '"', '\\', '{', '}', '<', '>', '|':
+				b.WriteByte('\\')
+				b.WriteRune(r)
+			case '\n':
+				b.WriteString("\\n")
+			default:
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -0,0 +1,35 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCache_WriteDOT(t *testing.T) {
+	cache := New[string, int](2, nil, nil, WithShadow[string, int](4))
+	cache.Put("a", 1)
+	cache.Put("b|c", 2)
+	cache.Put("d", 3)
+
+	var buf bytes.Buffer
+	if err := cache.WriteDOT(&buf); err != nil {
+		panic(err)
+	}
+	out := buf.String()
+	t.Log(out)
+	if !strings.HasPrefix(out, "digraph lru {") || !strings.HasSuffix(out, "}\n") {
+		panic(out)
+	}
+	if !strings.Contains(out, `e0 [label="d|size 1|age`) || !strings.Contains(out, `b\|c`) || !strings.Contains(out, "e0 -> e1") {
+		panic(out)
+	}
+	if strings.Contains(out, `e2 `) { // a 已被淘汰，只出现在影子缓存中
+		panic(out)
+	}
+	if !strings.Contains(out, "cluster_shadow") || !strings.Contains(out, `shadow2 [label="a|size 1"]`) {
+		panic(out)
+	}
+}
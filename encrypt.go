@@ -0,0 +1,66 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// ErrDecrypt 加密数据无法解密：未配置密钥或密钥错误
+var ErrDecrypt = errors.New("lru: decryption failed")
+
+// WithSnapshotEncryption 使用 AES-GCM 加密快照和预写日志，key 长度为 16、24 或 32 字节，分别对应 AES-128/192/256
+// 每条记录单独加密，校验和针对密文计算。key 长度非法时 SaveToFile/OpenWAL 等返回错误。
+// 未加密的快照仍可加载，便于从未加密迁移
+func WithSnapshotEncryption[K comparable, V interface{}](key []byte) Option[K, V] {
+	key = append([]byte(nil), key...)
+	return func(o *options[K, V]) {
+		o.snapshotKey = key
+	}
+}
+
+// newAEAD 由密钥创建 AEAD，key 为空时返回 nil 表示不加密
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) == 0 {
+		return nil, nil
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("lru: snapshot encryption: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// checkSnapshotKey 校验 WithSnapshotEncryption 的密钥
+func checkSnapshotKey(key []byte) error {
+	_, err := newAEAD(key)
+	return err
+}
+
+// seal 加密，输出为 nonce + 密文，unseal 为其逆过程
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func unseal(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if aead == nil {
+		return nil, fmt.Errorf("%w: data is encrypted but no key configured", ErrDecrypt)
+	}
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: ciphertext too short", ErrCorrupted)
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return plaintext, nil
+}
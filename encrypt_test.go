@@ -0,0 +1,77 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWithSnapshotEncryption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	key := bytes.Repeat([]byte{1}, 32)
+	cache := New[string, string](10, nil, nil, WithSnapshotEncryption[string, string](key))
+	cache.Put("user", "secret-value")
+	if err := cache.SaveToFile(path); err != nil {
+		panic(err)
+	}
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("secret-value")) {
+		panic("plaintext in snapshot")
+	}
+
+	loaded := New[string, string](10, nil, nil, WithSnapshotEncryption[string, string](key))
+	if err := loaded.LoadFromFile(path); err != nil {
+		panic(err)
+	}
+	if value, _ := loaded.Get("user"); value != "secret-value" {
+		panic(value)
+	}
+
+	if err := New[string, string](10, nil, nil).LoadFromFile(path); !errors.Is(err, ErrDecrypt) {
+		panic(err)
+	}
+	wrongKey := bytes.Repeat([]byte{2}, 32)
+	if err := New[string, string](10, nil, nil, WithSnapshotEncryption[string, string](wrongKey)).LoadFromFile(path); !errors.Is(err, ErrDecrypt) {
+		panic(err)
+	}
+	if err := New[string, string](10, nil, nil, WithSnapshotEncryption[string, string]([]byte("short"))).SaveToFile(path); err == nil {
+		panic("invalid key accepted")
+	}
+}
+
+func TestWithSnapshotEncryption_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[int, string](10, nil, nil)
+	if err := cache.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	cache.Put(1, "plain")
+	_ = cache.CloseWAL()
+
+	// 未加密的日志在开启加密后被压缩为加密快照
+	key := bytes.Repeat([]byte{1}, 16)
+	encrypted := New[int, string](10, nil, nil, WithSnapshotEncryption[int, string](key))
+	if err := encrypted.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	encrypted.Put(2, "secret-value")
+	_ = encrypted.CloseWAL()
+	data, _ := os.ReadFile(path)
+	if bytes.Contains(data, []byte("secret-value")) {
+		panic("plaintext in WAL")
+	}
+
+	recovered := New[int, string](10, nil, nil, WithSnapshotEncryption[int, string](key))
+	if err := recovered.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	defer recovered.CloseWAL()
+	if !reflect.DeepEqual(recovered.AllKeys(), []int{2, 1}) {
+		panic(recovered.AllKeys())
+	}
+}
@@ -0,0 +1,36 @@
+package lru
+
+import "time"
+
+// WithEqualityCheck 写入的 value 与已有的 value 相等时跳过这次写入，只把该项移到最前
+// 跳过的写入不触发监听器事件，也不重新计算大小和配额，适用于反复写入相同数据的刷新循环。
+// 过期时间仍按这次写入更新，开启预写日志时会记录新的过期时间。已过期或已失效的项正常写入
+func WithEqualityCheck[K comparable, V interface{}](equal func(a, b V) bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.equal = equal
+	}
+}
+
+// skipEqualUnlock 若 key 已有相等的 value 则只更新访问顺序和过期时间，返回是否跳过写入
+func (c *Cache[K, V]) skipEqualUnlock(key K, value V, expireAt time.Time) bool {
+	if c.equal == nil {
+		return false
+	}
+	ele, ok := c.m[key]
+	if !ok {
+		return false
+	}
+	now := time.Now()
+	e := ele.Value.(*Entry[K, V])
+	if e.stale || e.expired(now) || !c.equal(e.value, value) {
+		return false
+	}
+	e.accessTime = now
+	e.softAt = time.Time{}
+	c.li.MoveToFront(ele)
+	if !e.expireAt.Equal(expireAt) {
+		e.expireAt = expireAt
+		c.logUnlock(opPut, key, e.value, expireAt)
+	}
+	return true
+}
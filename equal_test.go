@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_WithEqualityCheck(t *testing.T) {
+	cache := New[int, string](10, nil, nil, WithEqualityCheck[int, string](func(a, b string) bool { return a == b }))
+	var events []EventType
+	cache.AddListener(func(event Event[int, string]) {
+		events = append(events, event.Type)
+	}, ListenerConfig{})
+
+	cache.Put(1, "a")
+	cache.Put(2, "b")
+	cache.Put(1, "a") // 相等，只移到最前
+	if !reflect.DeepEqual(cache.AllKeys(), []int{1, 2}) {
+		panic(cache.AllKeys())
+	}
+	if len(events) != 2 {
+		panic(events)
+	}
+
+	cache.Put(1, "c")
+	if value, _ := cache.Get(1); value != "c" || len(events) != 3 {
+		panic(value)
+	}
+
+	cache.Invalidate(1)
+	cache.Put(1, "c") // 已失效，正常写入
+	if _, ok := cache.Get(1); !ok || len(events) != 4 {
+		panic(events)
+	}
+}
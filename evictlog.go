@@ -0,0 +1,69 @@
+package lru
+
+import "time"
+
+// EvictedEntry 被淘汰或过期移除的缓存项记录
+type EvictedEntry[K comparable, V interface{}] struct {
+	Key        K
+	Value      V         // 仅在 WithEvictionLog 的 keepValues 为 true 时保存
+	Reason     EventType // EventEvict 或 EventExpire
+	CreateTime time.Time
+	AccessTime time.Time
+	RemoveTime time.Time
+	Hits       uint64
+}
+
+// WithEvictionLog 保留最近 n 个因容量不足淘汰或过期移除的项，用于排查 key 消失的原因，见 RecentlyEvicted
+// keepValues 为 false 时只保留元数据，不持有 value 的引用
+func WithEvictionLog[K comparable, V interface{}](n int, keepValues bool) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.evictLogSize = n
+		o.evictLogValues = keepValues
+	}
+}
+
+// evictLog 被移除项的环形缓冲区
+type evictLog[K comparable, V interface{}] struct {
+	ring []EvictedEntry[K, V]
+	head int // 环满后最早一条记录的下标
+}
+
+// recordEvictUnlock 记录被淘汰或过期移除的项
+func (c *Cache[K, V]) recordEvictUnlock(e *Entry[K, V], reason EventType) {
+	if c.evictLogSize <= 0 {
+		return
+	}
+	record := EvictedEntry[K, V]{
+		Key:        e.key,
+		Reason:     reason,
+		CreateTime: e.createTime,
+		AccessTime: e.accessTime,
+		RemoveTime: time.Now(),
+		Hits:       e.Hits(),
+	}
+	if c.evictLogValues {
+		record.Value = e.value
+	}
+	log := &c.evictLog
+	if cap(log.ring) != c.evictLogSize { // 首次记录或 Configure 修改了大小
+		log.ring, log.head = make([]EvictedEntry[K, V], 0, c.evictLogSize), 0
+	}
+	if len(log.ring) < cap(log.ring) {
+		log.ring = append(log.ring, record)
+		return
+	}
+	log.ring[log.head] = record
+	log.head = (log.head + 1) % len(log.ring)
+}
+
+// RecentlyEvicted 按移除先后返回最近被淘汰或过期移除的项，未开启 WithEvictionLog 时返回 nil
+func (c *Cache[K, V]) RecentlyEvicted() []EvictedEntry[K, V] {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if len(c.evictLog.ring) == 0 {
+		return nil
+	}
+	entries := make([]EvictedEntry[K, V], 0, len(c.evictLog.ring))
+	entries = append(entries, c.evictLog.ring[c.evictLog.head:]...)
+	return append(entries, c.evictLog.ring[:c.evictLog.head]...)
+}
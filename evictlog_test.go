@@ -0,0 +1,38 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_RecentlyEvicted(t *testing.T) {
+	cache := New[int, int](2, nil, nil, WithEvictionLog[int, int](2, true))
+	if cache.RecentlyEvicted() != nil {
+		panic("not empty")
+	}
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i*10)
+	}
+	evicted := cache.RecentlyEvicted()
+	if len(evicted) != 2 || evicted[0].Key != 1 || evicted[1].Key != 2 || evicted[1].Value != 20 {
+		panic(evicted)
+	}
+	if evicted[1].Reason != EventEvict {
+		panic(evicted[1].Reason)
+	}
+
+	cache.PutWithTTL(5, 50, time.Nanosecond)
+	cache.Get(5)
+	cache.Remove(4) // 主动移除不记录
+	evicted = cache.RecentlyEvicted()
+	if last := evicted[len(evicted)-1]; last.Key != 5 || last.Reason != EventExpire {
+		panic(evicted)
+	}
+
+	noValue := New[int, int](1, nil, nil, WithEvictionLog[int, int](10, false))
+	noValue.Put(1, 1)
+	noValue.Put(2, 2)
+	if evicted = noValue.RecentlyEvicted(); len(evicted) != 1 || evicted[0].Value != 0 {
+		panic(evicted)
+	}
+}
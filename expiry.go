@@ -0,0 +1,12 @@
+package lru
+
+import "time"
+
+// WithExpiry 由 KV 计算过期时长，例如读取 value 中保存的源站 Cache-Control max-age
+// Put 和 ttl <= 0 的 PutWithTTL 使用 expiry 的结果，显式传入的 ttl > 0 优先。expiry 返回 <= 0 表示永不过期。
+// expiry 在持有写锁时调用，不能调用缓存的方法
+func WithExpiry[K comparable, V interface{}](expiry func(key K, value V) time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.expiry = expiry
+	}
+}
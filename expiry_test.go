@@ -0,0 +1,27 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_WithExpiry(t *testing.T) {
+	// value 即 max-age
+	cache := New[string, time.Duration](10, nil, nil, WithExpiry(func(key string, maxAge time.Duration) time.Duration {
+		return maxAge
+	}))
+	cache.Put("short", 20*time.Millisecond)
+	cache.Put("forever", 0)
+	cache.PutWithTTL("explicit", 20*time.Millisecond, time.Hour) // 显式 ttl 优先
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.Get("short"); ok {
+		panic("short not expired")
+	}
+	if _, ok := cache.Get("forever"); !ok {
+		panic("forever expired")
+	}
+	if _, ok := cache.Get("explicit"); !ok {
+		panic("explicit expired")
+	}
+}
@@ -0,0 +1,154 @@
+package lru
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// WithExpiryWarning 缓存项过期前 lead 时间向监听发送 EventExpiring 事件，Value 为当前的 value，
+// 便于在过期之前续租或刷新。由一个后台 goroutine 按时间顺序发送，New 或 Configure 开启时启动，持有缓存直到 Close，开启后必须调用 Close。
+// 过期时间被再次写入改变后按新的时间发送，项在发送前被移除则不发送；剩余存活时间不足 lead 的项尽快发送。lead <= 0 表示不开启
+func WithExpiryWarning[K comparable, V interface{}](lead time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.warnLead = lead
+	}
+}
+
+// warning 一次待发送的过期预警
+type warning[K comparable] struct {
+	at       time.Time
+	key      K
+	expireAt time.Time
+}
+
+// warningHeap 按发送时间排序的最小堆
+type warningHeap[K comparable] []warning[K]
+
+func (h warningHeap[K]) Len() int            { return len(h) }
+func (h warningHeap[K]) Less(i, j int) bool  { return h[i].at.Before(h[j].at) }
+func (h warningHeap[K]) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *warningHeap[K]) Push(x interface{}) { *h = append(*h, x.(warning[K])) }
+func (h *warningHeap[K]) Pop() interface{} {
+	old := *h
+	w := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return w
+}
+
+// expiryWarner 过期预警的状态，scheduled 为每个 key 最新的过期时间，堆中过期时间不一致的是旧的预警
+type expiryWarner[K comparable] struct {
+	heap      warningHeap[K]
+	scheduled map[K]time.Time
+	wake      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// warnUnlock 为写入的 key 安排过期预警
+func (c *Cache[K, V]) warnUnlock(key K, expireAt time.Time) {
+	if c.warnLead <= 0 || expireAt.IsZero() || c.warnClosed {
+		return
+	}
+	w := c.warner
+	if w == nil {
+		return
+	}
+	if at, ok := w.scheduled[key]; ok && at.Equal(expireAt) {
+		return
+	}
+	w.scheduled[key] = expireAt
+	if len(w.heap) > 2*len(w.scheduled)+64 { // 频繁更新的 key 留下大量旧的预警，重建
+		w.heap = w.heap[:0]
+		for k, e := range w.scheduled {
+			w.heap = append(w.heap, warning[K]{at: e.Add(-c.warnLead), key: k, expireAt: e})
+		}
+		heap.Init(&w.heap)
+	}
+	heap.Push(&w.heap, warning[K]{at: expireAt.Add(-c.warnLead), key: key, expireAt: expireAt})
+	if w.heap[0].key == key {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// startWarnerUnlock 开启 WithExpiryWarning 时启动发送预警的 goroutine
+func (c *Cache[K, V]) startWarnerUnlock() {
+	if c.warnLead <= 0 || c.warner != nil || c.warnClosed {
+		return
+	}
+	w := &expiryWarner[K]{
+		scheduled: map[K]time.Time{},
+		wake:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	c.warner = w
+	go c.pprofDo(context.Background(), "expiry_warning", func(context.Context) { c.warnLoop(w) })
+}
+
+func (c *Cache[K, V]) warnLoop(w *expiryWarner[K]) {
+	defer close(w.done)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		c.lock.Lock()
+		next := c.fireWarningsUnlock(w, time.Now())
+		c.lock.Unlock()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case <-w.stop:
+			return
+		case <-w.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// fireWarningsUnlock 发送到期的预警，返回下一次预警的时间，没有时返回零值
+func (c *Cache[K, V]) fireWarningsUnlock(w *expiryWarner[K], now time.Time) time.Time {
+	for len(w.heap) > 0 && !w.heap[0].at.After(now) {
+		warn := heap.Pop(&w.heap).(warning[K])
+		if at, ok := w.scheduled[warn.key]; !ok || !at.Equal(warn.expireAt) {
+			continue
+		}
+		delete(w.scheduled, warn.key)
+		ele, ok := c.m[warn.key]
+		if !ok {
+			continue
+		}
+		if e := ele.Value.(*Entry[K, V]); c.warnLead > 0 && e.expireAt.Equal(warn.expireAt) && !e.expired(now) {
+			c.dispatchUnlock(Event[K, V]{Type: EventExpiring, Key: e.key, Value: e.value})
+		}
+	}
+	if len(w.heap) == 0 {
+		return time.Time{}
+	}
+	return w.heap[0].at
+}
+
+// stopWarner 停止过期预警的 goroutine，之后不再安排预警
+func (c *Cache[K, V]) stopWarner() {
+	c.lock.Lock()
+	w := c.warner
+	c.warner = nil
+	c.warnClosed = true
+	c.lock.Unlock()
+	if w != nil {
+		close(w.stop)
+		<-w.done
+	}
+}
@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCache_WithExpiryWarning(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithExpiryWarning[string, int](50*time.Millisecond))
+	defer cache.Close()
+	var lock sync.Mutex
+	var warned []string
+	cache.AddListener(func(e Event[string, int]) {
+		if e.Type == EventExpiring {
+			lock.Lock()
+			warned = append(warned, e.Key)
+			lock.Unlock()
+		}
+	}, ListenerConfig{})
+	got := func() []string {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]string(nil), warned...)
+	}
+
+	waitFor := func(want []string) {
+		for deadline := time.Now().Add(time.Second); !reflect.DeepEqual(got(), want); time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				panic(got())
+			}
+		}
+	}
+
+	cache.PutWithTTL("a", 1, 80*time.Millisecond)
+	cache.PutWithTTL("b", 2, 100*time.Millisecond)
+	cache.PutWithTTL("c", 3, 100*time.Millisecond)
+	cache.PutWithTTL("renewed", 4, 60*time.Millisecond)
+	cache.Put("forever", 5)
+	cache.Remove("c")
+	cache.PutWithTTL("renewed", 4, time.Hour) // 续期后按新的过期时间预警
+	if len(got()) != 0 {
+		panic(got())
+	}
+	waitFor([]string{"a", "b"})
+
+	// 剩余时间不足 lead 的项尽快预警
+	cache.PutWithTTL("short", 6, 10*time.Millisecond)
+	waitFor([]string{"a", "b", "short"})
+
+	cache.Close()
+	if cache.warner != nil {
+		panic("warner not stopped")
+	}
+	cache.PutWithTTL("closed", 7, time.Millisecond)
+	if len(got()) != 3 || cache.warner != nil {
+		panic(got())
+	}
+}
+
+func TestMemoize_WithExpiryWarning(t *testing.T) {
+	cache := New[string, int](10, nil, nil, withoutClose([]Option[string, int]{WithExpiryWarning[string, int](time.Minute)})...)
+	if cache.warner != nil || cache.warnLead != 0 {
+		panic("warner started without Close")
+	}
+	cache = New[string, int](10, nil, nil, WithExpiryWarning[string, int](time.Minute))
+	defer cache.Close()
+	if cache.warner == nil {
+		panic("warner not started by New")
+	}
+}
@@ -0,0 +1,90 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Format ExportRecords 的输出格式
+type Format int
+
+const (
+	FormatCSV   Format = iota // 带表头的 CSV，key 使用 fmt.Sprint 格式化
+	FormatJSONL               // 每行一个 JSON 对象，key 使用 json 编码
+)
+
+// exportRecord JSON Lines 的一行
+type exportRecord struct {
+	Key        json.RawMessage `json:"key"`
+	Size       int             `json:"size"`
+	AgeSeconds float64         `json:"age_seconds"`
+	Hits       uint64          `json:"hits"`
+	TTLSeconds *float64        `json:"ttl_seconds"` // 剩余存活时间，永不过期时为 null
+}
+
+// ExportRecords 按照访问先后（WithStableIterationOrder 时按写入先后）逐条输出每项的 key、大小、写入至今的时长、命中次数和剩余存活时间，用于容量分析
+// 输出是流式的，不会在内存中拼接全部内容，但输出期间持有读锁，w 较慢时会阻塞写操作
+func (c *Cache[K, V]) ExportRecords(w io.Writer, format Format) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	now := time.Now()
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"key", "size", "age_seconds", "hits", "ttl_seconds"}); err != nil {
+			return err
+		}
+		var err error
+		c.rangeUnlock(func(e *Entry[K, V]) bool {
+			ttl := ""
+			if !e.expireAt.IsZero() {
+				ttl = strconv.FormatFloat(e.expireAt.Sub(now).Seconds(), 'f', 3, 64)
+			}
+			err = cw.Write([]string{
+				fmt.Sprint(e.key),
+				strconv.Itoa(c.sizeOf(e.key, e.value)),
+				strconv.FormatFloat(now.Sub(e.createTime).Seconds(), 'f', 3, 64),
+				strconv.FormatUint(atomic.LoadUint64(&e.hits), 10),
+				ttl,
+			})
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatJSONL:
+		encoder := json.NewEncoder(w)
+		var err error
+		c.rangeUnlock(func(e *Entry[K, V]) bool {
+			var key []byte
+			if key, err = json.Marshal(e.key); err != nil {
+				return false
+			}
+			r := exportRecord{
+				Key:        key,
+				Size:       c.sizeOf(e.key, e.value),
+				AgeSeconds: now.Sub(e.createTime).Seconds(),
+				Hits:       atomic.LoadUint64(&e.hits),
+			}
+			if !e.expireAt.IsZero() {
+				ttl := e.expireAt.Sub(now).Seconds()
+				r.TTLSeconds = &ttl
+			}
+			err = encoder.Encode(&r)
+			return err == nil
+		})
+		return err
+	default:
+		return fmt.Errorf("lru: unknown export format %d", format)
+	}
+}
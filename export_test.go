@@ -0,0 +1,73 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCache_ExportRecords(t *testing.T) {
+	cache := New[string, string](10, nil, func(key string, value string) int { return len(value) })
+	cache.Put("a", "1")
+	cache.PutWithTTL("b", "22", time.Hour)
+	_, _ = cache.Get("a")
+	_, _ = cache.GetNoMove("a")
+
+	var buf bytes.Buffer
+	if err := cache.ExportRecords(&buf, FormatCSV); err != nil {
+		panic(err)
+	}
+	t.Log(buf.String())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[1], "a,1,") || !strings.HasSuffix(lines[1], ",2,") {
+		panic(lines)
+	}
+	if !strings.HasPrefix(lines[2], "b,2,") || strings.HasSuffix(lines[2], ",") {
+		panic(lines)
+	}
+
+	buf.Reset()
+	if err := cache.ExportRecords(&buf, FormatJSONL); err != nil {
+		panic(err)
+	}
+	t.Log(buf.String())
+	decoder := json.NewDecoder(&buf)
+	var r exportRecord
+	if err := decoder.Decode(&r); err != nil {
+		panic(err)
+	}
+	if string(r.Key) != `"a"` || r.Hits != 2 || r.TTLSeconds != nil {
+		panic(r)
+	}
+	if err := decoder.Decode(&r); err != nil {
+		panic(err)
+	}
+	if r.Size != 2 || r.TTLSeconds == nil || *r.TTLSeconds <= 0 {
+		panic(r)
+	}
+
+	if err := cache.ExportRecords(&buf, Format(100)); err == nil {
+		panic("unknown format")
+	}
+}
+
+func TestCache_ExportRecords_StableOrder(t *testing.T) {
+	cache := New[string, int](3, nil, nil, WithStableIterationOrder[string, int]())
+	cache.Put("b", 2)
+	cache.Put("d", 4)
+	cache.Put("a", 1)
+	cache.Get("b")
+
+	var buf bytes.Buffer
+	if err := cache.ExportRecords(&buf, FormatCSV); err != nil {
+		panic(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[1], "b,") || !strings.HasPrefix(lines[3], "a,") {
+		panic(lines)
+	}
+}
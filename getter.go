@@ -0,0 +1,53 @@
+package lru
+
+import "time"
+
+// Getter 按 key 读取数据，例如数据库或远程服务的访问层
+type Getter[K comparable, V interface{}] interface {
+	Get(key K) (V, error)
+}
+
+// GetterFunc 将普通函数转换为 Getter
+type GetterFunc[K comparable, V interface{}] func(key K) (V, error)
+
+func (f GetterFunc[K, V]) Get(key K) (V, error) {
+	return f(key)
+}
+
+// cachedGetter Wrap 返回的带缓存的 Getter
+type cachedGetter[K comparable, V interface{}] struct {
+	getter Getter[K, V]
+	cache  *Cache[K, V]
+	ttl    time.Duration
+}
+
+// Wrap 返回带缓存的 g，读取通过 Do 进行：同一 key 的并发读取只调用一次 g，结果以 ttl 缓存，
+// ttl > 0 时 g 返回的错误也缓存 ttl 时长。maxSize 和 opts 与 New 相同，但内部缓存无法 Close，忽略 WithExpiryWarning
+func Wrap[K comparable, V interface{}](g Getter[K, V], maxSize int, ttl time.Duration, opts ...Option[K, V]) Getter[K, V] {
+	return &cachedGetter[K, V]{getter: g, cache: New[K, V](maxSize, nil, nil, withoutClose(opts)...), ttl: ttl}
+}
+
+func (cg *cachedGetter[K, V]) Get(key K) (V, error) {
+	return cg.cache.Do(key, func() (V, error) { return cg.getter.Get(key) }, cg.ttl)
+}
+
+// memoizeMaxSize Memoize 未设置 WithMaxSize 时的容量
+const memoizeMaxSize = 1024
+
+// Memoize 返回带缓存的 f：同一 key 的并发调用只执行一次 f，结果缓存在内部的 Cache 中
+// 容量默认为 1024，用 WithMaxSize、WithSizeCal 修改；结果默认永不过期，用 WithExpiry 按结果设置存活时间。
+// f 返回的错误不缓存。其余 opts 与 New 相同，例如 WithRefreshAhead、WithCircuitBreaker，但内部缓存无法 Close，忽略 WithExpiryWarning
+func Memoize[K comparable, V interface{}](f func(key K) (V, error), opts ...Option[K, V]) func(key K) (V, error) {
+	cache := New[K, V](memoizeMaxSize, nil, nil, withoutClose(opts)...)
+	return func(key K) (V, error) {
+		return cache.Do(key, func() (V, error) { return f(key) }, 0)
+	}
+}
+
+// withoutClose 在 opts 之后关闭需要 Close 才能停止的后台 goroutine，用于调用方拿不到缓存的 Wrap 和 Memoize
+func withoutClose[K comparable, V interface{}](opts []Option[K, V]) []Option[K, V] {
+	return append(opts[:len(opts):len(opts)], func(o *options[K, V]) {
+		o.warnLead = 0
+		o.reapDeadline = false
+	})
+}
@@ -0,0 +1,77 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWrap(t *testing.T) {
+	calls := map[int]int{}
+	errNotFound := errors.New("not found")
+	g := Wrap[int, string](GetterFunc[int, string](func(key int) (string, error) {
+		calls[key]++
+		if key < 0 {
+			return "", errNotFound
+		}
+		return "v", nil
+	}), 10, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		if value, err := g.Get(1); value != "v" || err != nil {
+			panic(err)
+		}
+		if _, err := g.Get(-1); !errors.Is(err, errNotFound) {
+			panic(err)
+		}
+	}
+	if calls[1] != 1 || calls[-1] != 1 {
+		panic(calls)
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	var calls atomic.Int64
+	square := Memoize(func(n int) (int, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		if n < 0 {
+			return 0, errors.New("negative")
+		}
+		return n * n, nil
+	}, WithMaxSize[int, int](2), WithExpiry(func(int, int) time.Duration { return 30 * time.Millisecond }))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v, err := square(3); err != nil || v != 9 {
+				panic(v)
+			}
+		}()
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		panic(calls.Load())
+	}
+
+	// 错误不缓存
+	if _, err := square(-1); err == nil {
+		panic("error expected")
+	}
+	_, _ = square(-1)
+	if calls.Load() != 3 {
+		panic(calls.Load())
+	}
+
+	// 过期后重新计算
+	time.Sleep(40 * time.Millisecond)
+	square(3)
+	t.Log(calls.Load())
+	if calls.Load() != 4 {
+		panic(calls.Load())
+	}
+}
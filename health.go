@@ -0,0 +1,96 @@
+package lru
+
+import (
+	"fmt"
+	"time"
+)
+
+// QueueDepth 异步队列的长度和容量
+type QueueDepth struct {
+	Len int
+	Cap int
+}
+
+// HealthReport 缓存健康状况，可以用作依赖缓存的服务的就绪探针
+type HealthReport struct {
+	Healthy bool // 以下各项均正常
+
+	Invariant error // 内部一致性检查，nil 表示一致
+
+	WALOpen        bool
+	WALError       error     // 写日志遇到的第一个错误
+	CompactorAlive bool      // 后台压缩 goroutine 是否在运行，OpenWAL 未设置 compactInterval 时为 false
+	LastSnapshot   time.Time // 最近一次成功写入快照的时间，零值表示尚未写入
+
+	ListenerQueues []QueueDepth // 每个异步监听器的队列，队列满时写操作会阻塞
+	ExpireBacklog  int          // WithExpireRateLimit 攒着等待批量处理的失效项数目
+	Refreshing     int          // 正在进行的后台刷新数目
+
+	Size    int
+	MaxSize int
+	Number  int
+}
+
+// Health 检查缓存的健康状况，一致性检查需要遍历所有项，期间持有读锁
+func (c *Cache[K, V]) Health() HealthReport {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	report := HealthReport{
+		Invariant:  c.checkUnlock(),
+		Refreshing: c.Refreshing(),
+		Size:       c.curSize,
+		MaxSize:    c.maxSize,
+		Number:     c.li.Len(),
+	}
+	report.Healthy = report.Invariant == nil
+	if nanos := c.lastSnapshot.Load(); nanos != 0 {
+		report.LastSnapshot = time.Unix(0, nanos)
+	}
+
+	c.walHealthUnlock(&report)
+
+	for _, l := range c.listeners {
+		if l.queue != nil {
+			depth := QueueDepth{Len: len(l.queue), Cap: cap(l.queue)}
+			report.ListenerQueues = append(report.ListenerQueues, depth)
+			report.Healthy = report.Healthy && depth.Len < depth.Cap
+		}
+	}
+
+	if c.expireLimiter != nil {
+		c.expireLimiter.lock.Lock()
+		report.ExpireBacklog = len(c.expireLimiter.keys)
+		c.expireLimiter.lock.Unlock()
+	}
+	return report
+}
+
+// checkUnlock 检查链表、map 和累计大小是否一致
+func (c *Cache[K, V]) checkUnlock() error {
+	if len(c.m) != c.li.Len() {
+		return fmt.Errorf("lru: map has %d entries but list has %d", len(c.m), c.li.Len())
+	}
+	size := 0
+	quotaSizes := map[string]int{}
+	for ele := c.li.Front(); ele != nil; ele = ele.Next() {
+		e := ele.Value.(*Entry[K, V])
+		if c.m[e.key] != ele {
+			return fmt.Errorf("lru: list entry %v not indexed by map", e.key)
+		}
+		s := c.sizeOf(e.key, e.value)
+		size += s
+		if c.quotaLabel != nil {
+			quotaSizes[c.quotaLabel(e.key)] += s
+		}
+	}
+	if size != c.curSize {
+		return fmt.Errorf("lru: size is %d but entries sum to %d", c.curSize, size)
+	}
+	for label, s := range quotaSizes {
+		if s != 0 && c.quotaSizes[label] != s {
+			return fmt.Errorf("lru: quota %q is %d but entries sum to %d", label, c.quotaSizes[label], s)
+		}
+	}
+	return nil
+}
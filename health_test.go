@@ -0,0 +1,26 @@
+package lru
+
+import "testing"
+
+func TestCache_Health(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	remove := cache.AddListener(func(event Event[int, int]) {}, ListenerConfig{Mode: DeliverOrdered, QueueSize: 16})
+	defer remove()
+
+	report := cache.Health()
+	if !report.Healthy || report.WALOpen || report.Number != 5 || len(report.ListenerQueues) != 1 {
+		panic(report)
+	}
+	if !report.LastSnapshot.IsZero() {
+		panic(report.LastSnapshot)
+	}
+
+	cache.curSize++ // 破坏一致性
+	if report = cache.Health(); report.Healthy || report.Invariant == nil {
+		panic(report)
+	}
+	t.Log(report.Invariant)
+}
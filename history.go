@@ -0,0 +1,91 @@
+package lru
+
+import "time"
+
+// SizeSample 某个时间段结束时的缓存占用
+type SizeSample struct {
+	Time   time.Time // 时间段的起点
+	Size   int
+	Number int
+}
+
+// WithSizeHistory 按 resolution 为一个时间段记录缓存大小和元素个数，保留最近 samples 个时间段
+// 采样是惰性的：缓存大小变化时更新当前时间段，没有变化的时间段沿用上一段的值，不需要后台协程
+func WithSizeHistory[K comparable, V interface{}](resolution time.Duration, samples int) Option[K, V] {
+	return func(o *options[K, V]) {
+		if resolution > 0 && samples > 0 {
+			o.history = &sizeHistory{resolution: resolution, ring: make([]SizeSample, 0, samples)}
+		}
+	}
+}
+
+// sizeHistory 大小历史环形缓冲区
+type sizeHistory struct {
+	resolution time.Duration
+	ring       []SizeSample
+	head       int // 环满后最早一个样本的下标
+}
+
+// last 返回最近一个样本
+func (h *sizeHistory) last() *SizeSample {
+	if len(h.ring) == 0 {
+		return nil
+	}
+	return &h.ring[(h.head+len(h.ring)-1)%len(h.ring)]
+}
+
+func (h *sizeHistory) push(sample SizeSample) {
+	if len(h.ring) < cap(h.ring) {
+		h.ring = append(h.ring, sample)
+		return
+	}
+	h.ring[h.head] = sample
+	h.head = (h.head + 1) % len(h.ring)
+}
+
+// record 记录 now 所在时间段的大小，中间没有变化的时间段用上一个样本补齐
+func (h *sizeHistory) record(now time.Time, size, number int) {
+	bucket := now.Truncate(h.resolution)
+	last := h.last()
+	if last != nil && !bucket.After(last.Time) {
+		last.Size, last.Number = size, number
+		return
+	}
+	if last != nil {
+		prev := *last
+		gap := int(bucket.Sub(prev.Time)/h.resolution) - 1
+		if gap > cap(h.ring) {
+			gap = cap(h.ring)
+		}
+		for i := gap; i > 0; i-- {
+			prev.Time = bucket.Add(-time.Duration(i) * h.resolution)
+			h.push(prev)
+		}
+	}
+	h.push(SizeSample{Time: bucket, Size: size, Number: number})
+}
+
+// samples 按时间先后返回全部样本
+func (h *sizeHistory) samples() []SizeSample {
+	samples := make([]SizeSample, 0, len(h.ring))
+	samples = append(samples, h.ring[h.head:]...)
+	return append(samples, h.ring[:h.head]...)
+}
+
+// sampleUnlock 缓存大小变化后更新历史
+func (c *Cache[K, V]) sampleUnlock() {
+	if c.history != nil {
+		c.history.record(time.Now(), c.curSize, c.li.Len())
+	}
+}
+
+// SizeHistory 按时间先后返回最近的大小历史，未开启 WithSizeHistory 时返回 nil
+func (c *Cache[K, V]) SizeHistory() []SizeSample {
+	if c.history == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.sampleUnlock() // 补齐到当前时间段
+	return c.history.samples()
+}
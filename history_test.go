@@ -0,0 +1,51 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSizeHistory_Record(t *testing.T) {
+	h := &sizeHistory{resolution: time.Second, ring: make([]SizeSample, 0, 3)}
+	base := time.Unix(1000, 0)
+	h.record(base, 1, 1)
+	h.record(base.Add(500*time.Millisecond), 2, 2) // 同一时间段覆盖
+	h.record(base.Add(3*time.Second), 5, 3)        // 补齐中间两个时间段
+	samples := h.samples()
+	if len(samples) != 3 {
+		panic(samples)
+	}
+	if samples[0].Size != 2 || samples[1].Size != 2 || samples[2].Size != 5 {
+		panic(samples)
+	}
+	if !samples[0].Time.Equal(base.Add(time.Second)) || !samples[2].Time.Equal(base.Add(3*time.Second)) {
+		panic(samples)
+	}
+
+	h.record(base.Add(time.Hour), 7, 4) // 间隔过长，只保留最近的
+	samples = h.samples()
+	if samples[0].Size != 5 || samples[2].Size != 7 {
+		panic(samples)
+	}
+}
+
+func TestCache_SizeHistory(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	if cache.SizeHistory() != nil {
+		panic("history not enabled")
+	}
+
+	cache = New[int, int](10, nil, nil, WithSizeHistory[int, int](time.Hour, 4))
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	samples := cache.SizeHistory()
+	if last := samples[len(samples)-1]; last.Size != 2 || last.Number != 2 {
+		panic(samples)
+	}
+	cache.RemoveAll()
+	samples = cache.SizeHistory()
+	if samples[len(samples)-1].Size != 0 {
+		panic(samples)
+	}
+	t.Log(samples)
+}
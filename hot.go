@@ -0,0 +1,49 @@
+package lru
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// WithHotKeyThreshold 缓存项的命中次数达到 threshold 时向监听发送 EventHot 事件，Value 为当前的 value，
+// 用于把热点 key 复制到本地 L1 缓存或 CDN。threshold 为 0 表示不发送。
+// 只有 Get 和 Do 的命中计入，GetNoMove 持有读锁，不会触发事件。每个缓存项越过阈值时发送一次，
+// 配合 WithFrequencyDecay 时命中次数近似为访问速率，衰减到阈值以下后再次变热会重新发送
+func WithHotKeyThreshold[K comparable, V interface{}](threshold uint64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.hotThreshold = threshold
+	}
+}
+
+// hotUnlock 第 hits 次命中恰好越过阈值时发送 EventHot
+func (c *Cache[K, V]) hotUnlock(e *Entry[K, V], hits uint64) {
+	if c.hotThreshold == 0 || hits != c.hotThreshold || len(c.listeners) == 0 {
+		return
+	}
+	c.dispatchUnlock(Event[K, V]{Type: EventHot, Key: e.key, Value: e.value})
+}
+
+// HotKeys 返回命中次数不低于 threshold 的未过期 key，按命中次数从高到低排序，相同时最近使用的在前
+// 开启 WithFrequencyDecay 时按衰减后的命中次数统计
+func (c *Cache[K, V]) HotKeys(threshold uint64) []K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	now := time.Now()
+	var hot []*Entry[K, V]
+	for ele := c.li.Front(); ele != nil; ele = ele.Next() {
+		e := ele.Value.(*Entry[K, V])
+		if e.expired(now) || atomic.LoadUint64(&e.hits) < threshold {
+			continue
+		}
+		hot = append(hot, e)
+	}
+	sort.SliceStable(hot, func(i, j int) bool {
+		return atomic.LoadUint64(&hot[i].hits) > atomic.LoadUint64(&hot[j].hits)
+	})
+	keys := make([]K, len(hot))
+	for i, e := range hot {
+		keys[i] = e.key
+	}
+	return keys
+}
@@ -0,0 +1,43 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_HotKeys(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithHotKeyThreshold[string, int](3))
+	var hot []string
+	cache.AddListener(func(e Event[string, int]) {
+		if e.Type == EventHot {
+			hot = append(hot, e.Key)
+		}
+	}, ListenerConfig{})
+
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	for i := 0; i < 5; i++ {
+		cache.Get("a")
+	}
+	for i := 0; i < 3; i++ {
+		cache.Get("b")
+	}
+	cache.Get("c")
+	cache.GetNoMove("c")
+	cache.GetNoMove("c") // GetNoMove 不触发事件
+
+	t.Log(hot)
+	if !reflect.DeepEqual(hot, []string{"a", "b"}) {
+		panic(hot)
+	}
+	if keys := cache.HotKeys(3); !reflect.DeepEqual(keys, []string{"a", "c", "b"}) {
+		panic(keys)
+	}
+	if keys := cache.HotKeys(4); !reflect.DeepEqual(keys, []string{"a"}) {
+		panic(keys)
+	}
+	if keys := cache.HotKeys(100); len(keys) != 0 {
+		panic(keys)
+	}
+}
@@ -0,0 +1,276 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	manifestName = "MANIFEST"
+	// maxDeltas 增量快照的数目达到该值后下一次保存写全量快照
+	maxDeltas = 16
+)
+
+var deltaMagic = [4]byte{'L', 'R', 'U', 'D'}
+
+// incrState SaveIncremental 的状态：目录中已有的快照，以及上次保存之后变化过的 key
+type incrState[K comparable] struct {
+	dir     string
+	seq     int      // 最近写入的文件序号
+	base    string   // 全量快照的文件名
+	deltas  []string // 增量快照的文件名，按写入顺序
+	dirty   map[K]struct{}
+	cleared bool // 上次保存之后缓存被清空过
+}
+
+// SaveIncremental 将缓存保存到目录 dir：第一次保存写全量快照，之后只写上次保存以来写入、移除、淘汰或过期的 key，
+// 目录中的 MANIFEST 记录全量快照和增量快照的顺序，用 LoadIncremental 加载。
+// 增量快照达到 16 个，或变化的 key 超过缓存项数目的一半时写新的全量快照并删除旧文件。
+// 只有访问顺序的变化（Get）不会写入增量快照，加载后变化过的 key 排在未变化的 key 之前。
+// 进程重启后需要先 LoadIncremental 同一目录，否则下一次保存写全量快照。保存期间持有写锁
+func (c *Cache[K, V]) SaveIncremental(dir string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	s := c.incr
+	if s == nil || s.dir != dir || len(s.deltas) >= maxDeltas || s.cleared || 2*len(s.dirty) > len(c.m) {
+		return c.saveBaseUnlock(dir)
+	}
+	if len(s.dirty) == 0 {
+		return nil
+	}
+	name := fmt.Sprintf("delta-%06d.snap", s.seq+1)
+	if err := c.saveDeltaUnlock(filepath.Join(dir, name)); err != nil {
+		return err
+	}
+	deltas := append(append([]string(nil), s.deltas...), name)
+	if err := writeManifest(dir, s.base, deltas); err != nil {
+		_ = os.Remove(filepath.Join(dir, name))
+		return err
+	}
+	s.seq++
+	s.deltas = deltas
+	s.dirty = map[K]struct{}{}
+	return nil
+}
+
+// saveBaseUnlock 写全量快照，更新 MANIFEST 后删除不再使用的快照
+func (c *Cache[K, V]) saveBaseUnlock(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	// 新的全量快照的序号大于目录中已有的快照，不会覆盖仍被 MANIFEST 引用的文件
+	seq := 0
+	if c.incr != nil && c.incr.dir == dir {
+		seq = c.incr.seq
+	}
+	if base, deltas, err := readManifest(dir); err == nil {
+		for _, name := range append(deltas, base) {
+			if n := parseSeq(name); n > seq {
+				seq = n
+			}
+		}
+	}
+	seq++
+	base := fmt.Sprintf("base-%06d.snap", seq)
+	if err := c.saveUnlock(filepath.Join(dir, base)); err != nil {
+		return err
+	}
+	if err := writeManifest(dir, base, nil); err != nil {
+		_ = os.Remove(filepath.Join(dir, base))
+		return err
+	}
+	removeStale(dir, base)
+	c.incr = &incrState[K]{dir: dir, seq: seq, base: base, dirty: map[K]struct{}{}}
+	return nil
+}
+
+// saveDeltaUnlock 写增量快照：变化过且仍在缓存中的 key 按访问先后写 opPut，已不在缓存中的写 opRemove
+func (c *Cache[K, V]) saveDeltaUnlock(path string) error {
+	aead, err := newAEAD(c.snapshotKey)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w *bufio.Writer) error {
+		if err := writeHeader(w, deltaMagic, aead); err != nil {
+			return err
+		}
+		for ele := c.li.Back(); ele != nil; ele = ele.Prev() {
+			e := ele.Value.(*Entry[K, V])
+			if _, ok := c.incr.dirty[e.key]; !ok {
+				continue
+			}
+			if err := writeRecord(w, aead, &record[K, V]{Op: opPut, Key: e.key, Value: e.value, ExpireAt: e.expireAt, Stale: e.stale}); err != nil {
+				return err
+			}
+		}
+		for key := range c.incr.dirty {
+			if _, ok := c.m[key]; ok {
+				continue
+			}
+			if err := writeRecord(w, aead, &record[K, V]{Op: opRemove, Key: key}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LoadIncremental 加载 SaveIncremental 写出的目录，依次应用全量快照和增量快照，之后的 SaveIncremental 继续写增量快照
+// 错误与 LoadFromFile 相同，出错时缓存不会被修改
+func (c *Cache[K, V]) LoadIncremental(dir string) error {
+	base, deltas, err := readManifest(dir)
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(c.snapshotKey)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, base))
+	if err != nil {
+		return err
+	}
+	records, err := readSnapshot[K, V](bytes.NewReader(data), aead)
+	if err != nil {
+		return err
+	}
+	for _, name := range deltas {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		delta, err := readDelta[K, V](data, aead)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		records = append(records, delta...)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.applyAllUnlock(records)
+	seq := parseSeq(base)
+	if len(deltas) > 0 {
+		seq = parseSeq(deltas[len(deltas)-1])
+	}
+	c.incr = &incrState[K]{dir: dir, seq: seq, base: base, deltas: deltas, dirty: map[K]struct{}{}}
+	return nil
+}
+
+// readDelta 读取增量快照，文件头标记为未加密时忽略 aead
+func readDelta[K comparable, V interface{}](data []byte, aead cipher.AEAD) ([]record[K, V], error) {
+	r := bytes.NewReader(data)
+	flags, err := readHeader(r, deltaMagic)
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header", ErrCorrupted)
+	}
+	if flags&flagEncrypted == 0 {
+		aead = nil
+	} else if aead == nil {
+		return nil, fmt.Errorf("%w: snapshot is encrypted but no key configured", ErrDecrypt)
+	}
+	records, _, err := readRecords[K, V](r, aead)
+	if err == io.ErrUnexpectedEOF {
+		err = fmt.Errorf("%w: truncated record", ErrCorrupted)
+	}
+	return records, err
+}
+
+// trackUnlock 记录上次 SaveIncremental 之后变化的 key
+func (c *Cache[K, V]) trackUnlock(op byte, key K) {
+	if c.incr == nil {
+		return
+	}
+	if op == opClear {
+		c.incr.cleared = true
+		c.incr.dirty = map[K]struct{}{}
+		return
+	}
+	c.incr.dirty[key] = struct{}{}
+}
+
+// writeManifest 原子地写 MANIFEST，每行为 "base 文件名" 或 "delta 文件名"
+func writeManifest(dir, base string, deltas []string) error {
+	return writeFileAtomic(filepath.Join(dir, manifestName), func(w *bufio.Writer) error {
+		fmt.Fprintf(w, "base %s\n", base)
+		for _, name := range deltas {
+			fmt.Fprintf(w, "delta %s\n", name)
+		}
+		return nil
+	})
+}
+
+func readManifest(dir string) (base string, deltas []string, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return "", nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		kind, name, ok := strings.Cut(line, " ")
+		switch {
+		case ok && kind == "base" && base == "":
+			base = name
+		case ok && kind == "delta" && base != "":
+			deltas = append(deltas, name)
+		default:
+			return "", nil, fmt.Errorf("%w: bad manifest line %q", ErrCorrupted, line)
+		}
+	}
+	return base, deltas, nil
+}
+
+// parseSeq 从快照文件名中解析序号
+func parseSeq(name string) int {
+	var seq int
+	if _, err := fmt.Sscanf(name[strings.IndexByte(name, '-')+1:], "%d", &seq); err != nil {
+		return 0
+	}
+	return seq
+}
+
+// removeStale 删除目录中不是 base 的快照文件
+func removeStale(dir, base string) {
+	for _, pattern := range []string{"base-*.snap", "delta-*.snap"} {
+		names, _ := filepath.Glob(filepath.Join(dir, pattern))
+		for _, name := range names {
+			if filepath.Base(name) != base {
+				_ = os.Remove(name)
+			}
+		}
+	}
+}
+
+// writeFileAtomic 先写临时文件，同步到磁盘后重命名为 path
+func writeFileAtomic(path string, write func(w *bufio.Writer) error) error {
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+	}
+	return err
+}
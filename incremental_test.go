@@ -0,0 +1,93 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCache_SaveIncremental(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	cache := New[int, int](100, nil, nil)
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	if err := cache.SaveIncremental(dir); err != nil {
+		panic(err)
+	}
+
+	cache.Put(1, 11)
+	cache.Remove(2)
+	if err := cache.SaveIncremental(dir); err != nil {
+		panic(err)
+	}
+	if err := cache.SaveIncremental(dir); err != nil { // 没有变化时不写文件
+		panic(err)
+	}
+	cache.Put(20, 20)
+	if err := cache.SaveIncremental(dir); err != nil {
+		panic(err)
+	}
+	files := listDir(dir)
+	t.Log(files)
+	if !reflect.DeepEqual(files, []string{"MANIFEST", "base-000001.snap", "delta-000002.snap", "delta-000003.snap"}) {
+		panic(files)
+	}
+
+	restored := New[int, int](100, nil, nil)
+	if err := restored.LoadIncremental(dir); err != nil {
+		panic(err)
+	}
+	if value, _ := restored.GetNoMove(1); value != 11 || restored.Number() != 10 {
+		panic(restored.AllKeys())
+	}
+	if _, ok := restored.GetNoMove(2); ok {
+		panic(2)
+	}
+	if _, ok := restored.GetNoMove(20); !ok {
+		panic(20)
+	}
+
+	// 加载后继续写增量快照
+	restored.Put(3, 33)
+	if err := restored.SaveIncremental(dir); err != nil {
+		panic(err)
+	}
+	if files := listDir(dir); len(files) != 5 || files[4] != "delta-000004.snap" {
+		panic(files)
+	}
+
+	// 清空后写全量快照，删除旧文件
+	restored.RemoveAll()
+	restored.Put(7, 7)
+	if err := restored.SaveIncremental(dir); err != nil {
+		panic(err)
+	}
+	if files := listDir(dir); !reflect.DeepEqual(files, []string{"MANIFEST", "base-000005.snap"}) {
+		panic(files)
+	}
+	again := New[int, int](100, nil, nil)
+	if err := again.LoadIncremental(dir); err != nil {
+		panic(err)
+	}
+	if keys := again.AllKeys(); !reflect.DeepEqual(keys, []int{7}) {
+		panic(keys)
+	}
+}
+
+func listDir(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		panic(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	return names
+}
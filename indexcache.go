@@ -0,0 +1,115 @@
+package lru
+
+import "sync"
+
+// ValueStore 存放 value 的外部存储，例如磁盘或对象存储
+type ValueStore[K comparable, V interface{}] interface {
+	Load(key K) (V, error)
+	Store(key K, value V) error
+	Delete(key K) error
+}
+
+// IndexCache 只在内存中保留 key 和 value 大小，value 存放在 ValueStore 中
+// 内存中的 LRU 充当准入和索引层：Put 时写入 store，Get 时从 store 读取，淘汰或移除时从 store 删除。
+// 删除在 Put/Get/Remove 返回前持有被删除 key 的锁进行，通过 Index() 修改索引引起的删除推迟到下一次 Put/Get/Remove。
+// 适用于 value 很大、但访问先后顺序需要常驻内存的场景
+type IndexCache[K comparable, V interface{}] struct {
+	index   *Cache[K, int] // key -> value 大小
+	store   ValueStore[K, V]
+	sizeCal func(key K, value V) int
+
+	lock    sync.Mutex
+	err     error // 淘汰时删除 value 遇到的第一个错误
+	pending []K   // 已从索引移除、等待从 store 删除的 key
+}
+
+// NewIndexCache 创建 IndexCache
+// maxSize 和 sizeCal 的含义与 New 相同，大小按 value 计算但 value 并不占用内存；sizeCal 可以为空，此时每项大小为 1
+func NewIndexCache[K comparable, V interface{}](maxSize int, store ValueStore[K, V], sizeCal func(key K, value V) int) *IndexCache[K, V] {
+	if sizeCal == nil {
+		sizeCal = func(key K, value V) int { return 1 }
+	}
+	ic := &IndexCache[K, V]{store: store, sizeCal: sizeCal}
+	ic.index = New[K, int](maxSize, ic.onExpire, func(key K, size int) int { return size })
+	return ic
+}
+
+// onExpire 在索引的锁内调用，此时不能等待 key 锁，先记下来，由 Put/Get/Remove 释放 key 锁之后删除
+func (ic *IndexCache[K, V]) onExpire(key K, _ int) {
+	ic.lock.Lock()
+	ic.pending = append(ic.pending, key)
+	ic.lock.Unlock()
+}
+
+// deletePending 持有各 key 的锁从 store 删除被移除的 value。期间 key 被重新写入索引时不删除，
+// 否则会删掉新写入的 value
+func (ic *IndexCache[K, V]) deletePending() {
+	ic.lock.Lock()
+	pending := ic.pending
+	ic.pending = nil
+	ic.lock.Unlock()
+	for _, key := range pending {
+		unlock := ic.index.LockKey(key)
+		ic.index.lock.RLock()
+		_, indexed := ic.index.m[key]
+		ic.index.lock.RUnlock()
+		var err error
+		if !indexed {
+			err = ic.store.Delete(key)
+		}
+		unlock()
+		if err != nil {
+			ic.lock.Lock()
+			if ic.err == nil {
+				ic.err = err
+			}
+			ic.lock.Unlock()
+		}
+	}
+}
+
+// Put 将 value 写入 store 并记录到索引，写入 store 失败时索引不变
+func (ic *IndexCache[K, V]) Put(key K, value V) error {
+	defer ic.deletePending()
+	unlock := ic.index.LockKey(key)
+	defer unlock()
+	if err := ic.store.Store(key, value); err != nil {
+		return err
+	}
+	ic.index.Put(key, ic.sizeCal(key, value))
+	return nil
+}
+
+// Get 若 key 在索引中则从 store 读取 value
+func (ic *IndexCache[K, V]) Get(key K) (value V, ok bool, err error) {
+	defer ic.deletePending()
+	unlock := ic.index.LockKey(key)
+	defer unlock()
+	if _, ok = ic.index.Get(key); !ok {
+		return value, false, nil
+	}
+	if value, err = ic.store.Load(key); err != nil {
+		return value, false, err
+	}
+	return value, true, nil
+}
+
+// Remove 从索引和 store 中移除 key
+func (ic *IndexCache[K, V]) Remove(key K) {
+	defer ic.deletePending()
+	unlock := ic.index.LockKey(key)
+	defer unlock()
+	ic.index.Remove(key)
+}
+
+// Index 返回内部的索引缓存，value 为各项的大小，可以用于 AllKeys、Stats 等只读操作
+func (ic *IndexCache[K, V]) Index() *Cache[K, int] {
+	return ic.index
+}
+
+// Err 返回淘汰或移除时从 store 删除 value 遇到的第一个错误
+func (ic *IndexCache[K, V]) Err() error {
+	ic.lock.Lock()
+	defer ic.lock.Unlock()
+	return ic.err
+}
@@ -0,0 +1,132 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type mapStore struct {
+	m map[string][]byte
+}
+
+func (s *mapStore) Load(key string) ([]byte, error) {
+	value, ok := s.m[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return value, nil
+}
+
+func (s *mapStore) Store(key string, value []byte) error {
+	s.m[key] = value
+	return nil
+}
+
+func (s *mapStore) Delete(key string) error {
+	delete(s.m, key)
+	return nil
+}
+
+func TestIndexCache(t *testing.T) {
+	store := &mapStore{m: map[string][]byte{}}
+	cache := NewIndexCache[string, []byte](10, store, func(key string, value []byte) int { return len(value) })
+	if err := cache.Put("a", make([]byte, 4)); err != nil {
+		panic(err)
+	}
+	_ = cache.Put("b", make([]byte, 4))
+	_ = cache.Put("c", make([]byte, 4)) // 淘汰 a
+
+	if _, ok, err := cache.Get("a"); ok || err != nil {
+		panic(err)
+	}
+	if _, ok := store.m["a"]; ok {
+		panic("a not deleted from store")
+	}
+	value, ok, err := cache.Get("b")
+	if !ok || err != nil || len(value) != 4 {
+		panic(err)
+	}
+
+	cache.Remove("b")
+	if len(store.m) != 1 || cache.Index().Size() != 4 {
+		panic(store.m)
+	}
+
+	delete(store.m, "c")
+	if _, _, err = cache.Get("c"); err == nil {
+		panic("expect load error")
+	}
+	if cache.Err() != nil {
+		panic(cache.Err())
+	}
+}
+
+// pausingStore Store 写入后等待 resume，模拟写入 store 与写入索引之间的间隙
+type pausingStore struct {
+	lock   sync.Mutex
+	m      map[string]int
+	pause  string
+	stored chan struct{}
+	resume chan struct{}
+}
+
+func (s *pausingStore) Load(key string) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.m[key], nil
+}
+
+func (s *pausingStore) Store(key string, value int) error {
+	s.lock.Lock()
+	s.m[key] = value
+	s.lock.Unlock()
+	if key == s.pause {
+		s.stored <- struct{}{}
+		<-s.resume
+	}
+	return nil
+}
+
+func (s *pausingStore) Delete(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.m, key)
+	return nil
+}
+
+func TestIndexCache_EvictDuringPut(t *testing.T) {
+	store := &pausingStore{m: map[string]int{}, stored: make(chan struct{}), resume: make(chan struct{})}
+	cache := NewIndexCache[string, int](2, store, nil)
+	_ = cache.Put("b", 1)
+	_ = cache.Put("c", 1)
+
+	// 重新写入 b 时，在写入 store 与写入索引之间旧的 b 被淘汰
+	store.pause = "b"
+	done := make(chan struct{})
+	go func() {
+		_ = cache.Put("b", 2)
+		close(done)
+	}()
+	<-store.stored
+	evicted := make(chan struct{})
+	go func() {
+		_ = cache.Put("a", 1) // 淘汰旧的 b
+		close(evicted)
+	}()
+	for {
+		if _, ok := cache.Index().GetNoMove("b"); !ok {
+			break
+		}
+	}
+	close(store.resume)
+	<-done
+	<-evicted
+
+	if _, ok := cache.Index().GetNoMove("b"); !ok {
+		panic(cache.Index().AllKeys())
+	}
+	if value, ok, err := cache.Get("b"); !ok || err != nil || value != 2 {
+		panic(value)
+	}
+}
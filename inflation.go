@@ -0,0 +1,43 @@
+package lru
+
+import (
+	"container/list"
+	"time"
+)
+
+// WithCostInflation 淘汰时在最久未访问的 window 个项中挑选有效权重最大的淘汰，而不是总是淘汰最后一个
+// weight 由大小和命中次数计算有效权重，命中越多权重应越小，为空时为 size / (1 + hits)。
+// 这样同样大的项中，经常复用的比冷数据更难被淘汰，纯按大小计算容量时大而热的数据不再吃亏。
+// 缓存大小仍按 sizeCal 计算，有效权重只影响淘汰哪一项
+func WithCostInflation[K comparable, V interface{}](window int, weight func(size int, hits uint64) float64) Option[K, V] {
+	if weight == nil {
+		weight = func(size int, hits uint64) float64 { return float64(size) / float64(1+hits) }
+	}
+	return func(o *options[K, V]) {
+		o.inflateWindow = window
+		o.inflateWeight = weight
+	}
+}
+
+// victimUnlock 选择因容量不足被淘汰的项，跳过最短驻留保护期内的项，没有可淘汰的项时返回 nil
+func (c *Cache[K, V]) victimUnlock(now time.Time) *list.Element {
+	var victim *list.Element
+	var maxWeight float64
+	considered := 0
+	for ele := c.li.Back(); ele != nil; ele = ele.Prev() {
+		e := ele.Value.(*Entry[K, V])
+		if c.protected(e, now) {
+			continue
+		}
+		if c.inflateWindow <= 1 {
+			return ele
+		}
+		if weight := c.inflateWeight(c.sizeOf(e.key, e.value), e.Hits()); victim == nil || weight > maxWeight {
+			victim, maxWeight = ele, weight
+		}
+		if considered++; considered >= c.inflateWindow {
+			break
+		}
+	}
+	return victim
+}
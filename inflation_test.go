@@ -0,0 +1,32 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_WithCostInflation(t *testing.T) {
+	sizeCal := func(key string, value int) int { return value }
+	cache := New[string, int](25, nil, sizeCal, WithCostInflation[string, int](3, nil))
+	cache.Put("hot", 10)
+	cache.Put("cold", 10)
+	for i := 0; i < 5; i++ {
+		cache.GetNoMove("hot") // 命中但不改变访问顺序，hot 仍然最久未访问
+	}
+	cache.Put("new", 5) // 25，未超出
+	cache.Put("more", 1)
+	// 窗口内 hot 10/6、cold 10/1、new 5/1，淘汰 cold 而不是最久未访问的 hot
+	if !reflect.DeepEqual(cache.AllKeys(), []string{"more", "new", "hot"}) {
+		panic(cache.AllKeys())
+	}
+
+	plain := New[string, int](25, nil, sizeCal)
+	plain.Put("hot", 10)
+	plain.Put("cold", 10)
+	plain.GetNoMove("hot")
+	plain.Put("new", 5)
+	plain.Put("more", 1)
+	if !reflect.DeepEqual(plain.AllKeys(), []string{"more", "new", "cold"}) {
+		panic(plain.AllKeys())
+	}
+}
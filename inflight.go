@@ -0,0 +1,21 @@
+package lru
+
+import "fmt"
+
+// InflightLimitError 同时加载的不同 key 数目达到 WithMaxInflightLoads 的上限时 Do 返回的错误
+type InflightLimitError struct {
+	Limit int
+}
+
+func (e *InflightLimitError) Error() string {
+	return fmt.Sprintf("lru: too many in-flight loads (limit %d)", e.Limit)
+}
+
+// WithMaxInflightLoads 限制 Do 同时加载的不同 key 数目，<= 0 表示不限
+// 超出上限的 key 不调用加载函数，有过期或已失效的旧值时返回旧值，否则返回 *InflightLimitError，
+// 防止大量不同 key 的扫描耗尽加载函数背后的 goroutine 和连接。等待已有加载的调用不受限制
+func WithMaxInflightLoads[K comparable, V interface{}](n int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxInflight = n
+	}
+}
@@ -0,0 +1,42 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_WithMaxInflightLoads(t *testing.T) {
+	cache := New[int, int](10, nil, nil, WithMaxInflightLoads[int, int](1))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.Do(1, func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		}, 0)
+	}()
+	<-started
+
+	_, err := cache.Do(2, func() (int, error) { return 2, nil }, 0)
+	var limitErr *InflightLimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != 1 {
+		panic(err)
+	}
+
+	// 有旧值时返回旧值
+	cache.PutWithTTL(3, 30, time.Nanosecond)
+	cache.Configure(WithCircuitBreaker[int, int](100, time.Second)) // 保留过期项
+	if value, err := cache.Do(3, func() (int, error) { return 3, nil }, 0); value != 30 || err != nil {
+		panic(err)
+	}
+
+	close(release)
+	<-done
+	if value, err := cache.Do(2, func() (int, error) { return 2, nil }, 0); value != 2 || err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,56 @@
+package lru
+
+import "time"
+
+// Invalidate 将 key 标记为失效，返回 key 是否存在
+// 与 Remove 不同，失效的项仍然保留在缓存中：Get/GetNoMove/Do 视其为未命中，GetStale 仍可取到旧值，
+// 调用方可以凭旧值中的 ETag、版本号等向数据源确认，未变化时调用 Revalidate 恢复，变化了则直接 Put 新值。
+// 失效的项仍占用缓存大小并参与淘汰
+func (c *Cache[K, V]) Invalidate(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key = c.resolveUnlock(key)
+	ele, ok := c.m[key]
+	if !ok {
+		return false
+	}
+	e := ele.Value.(*Entry[K, V])
+	if !e.stale {
+		e.stale = true
+		c.logUnlock(opInvalidate, key, e.value, e.expireAt)
+	}
+	return true
+}
+
+// GetStale 获取 value 以及是否已被 Invalidate，不会将命中的 KV 对移动到头部
+// 已过期的项返回 false
+func (c *Cache[K, V]) GetStale(key K) (value V, stale bool, ok bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	key = c.resolveUnlock(key)
+	ele, ok := c.m[key]
+	if !ok || ele.Value.(*Entry[K, V]).expired(time.Now()) {
+		return value, false, false
+	}
+	e := ele.Value.(*Entry[K, V])
+	return e.value, e.stale, true
+}
+
+// Revalidate 清除 key 的失效标记并将其移动到头部，返回 key 是否存在且未过期
+func (c *Cache[K, V]) Revalidate(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key = c.resolveUnlock(key)
+	ele, ok := c.m[key]
+	if !ok || ele.Value.(*Entry[K, V]).expired(time.Now()) {
+		return false
+	}
+	e := ele.Value.(*Entry[K, V])
+	if e.stale {
+		e.stale = false
+		c.logUnlock(opPut, key, e.value, e.expireAt)
+	}
+	e.accessTime = time.Now()
+	c.li.MoveToFront(ele)
+	return true
+}
@@ -0,0 +1,67 @@
+package lru
+
+import "testing"
+
+func TestCache_Invalidate(t *testing.T) {
+	cache := New[string, string](10, nil, nil)
+	cache.Put("page", "etag-1")
+	cache.Put("other", "x")
+	if !cache.Invalidate("page") || cache.Invalidate("missing") {
+		panic("Invalidate")
+	}
+
+	if _, ok := cache.Get("page"); ok {
+		panic("stale hit")
+	}
+	if _, ok := cache.GetNoMove("page"); ok {
+		panic("stale hit")
+	}
+	value, stale, ok := cache.GetStale("page")
+	if !ok || !stale || value != "etag-1" {
+		panic(value)
+	}
+
+	if !cache.Revalidate("page") {
+		panic("Revalidate")
+	}
+	if value, ok := cache.Get("page"); !ok || value != "etag-1" {
+		panic(value)
+	}
+	if keys := cache.AllKeys(); keys[0] != "page" {
+		panic(keys)
+	}
+
+	cache.Invalidate("page")
+	cache.Put("page", "etag-2")
+	if _, stale, _ := cache.GetStale("page"); stale {
+		panic("Put keeps stale")
+	}
+}
+
+func TestCache_Invalidate_Alias(t *testing.T) {
+	cache := New[string, string](10, nil, nil)
+	cache.Put("page", "etag-1")
+	cache.Put("other", "x")
+	if !cache.Alias("url", "page") {
+		panic("Alias")
+	}
+	if !cache.Invalidate("url") {
+		panic("Invalidate")
+	}
+	if _, ok := cache.Get("page"); ok {
+		panic("stale hit")
+	}
+	value, stale, ok := cache.GetStale("url")
+	if !ok || !stale || value != "etag-1" {
+		panic(value)
+	}
+	if !cache.Revalidate("url") {
+		panic("Revalidate")
+	}
+	if value, ok := cache.Get("page"); !ok || value != "etag-1" {
+		panic(value)
+	}
+	if keys := cache.AllKeys(); keys[0] != "page" {
+		panic(keys)
+	}
+}
@@ -0,0 +1,25 @@
+package lru
+
+// Key2 由两部分组成的 key，例如 (租户, id)，可以直接作为 Cache 的 key 使用
+// 相比拼接字符串，不需要额外分配内存，也不会因为分隔符出现在字段中而冲突
+type Key2[A, B comparable] struct {
+	A A
+	B B
+}
+
+// K2 创建 Key2
+func K2[A, B comparable](a A, b B) Key2[A, B] {
+	return Key2[A, B]{A: a, B: b}
+}
+
+// Key3 由三部分组成的 key
+type Key3[A, B, C comparable] struct {
+	A A
+	B B
+	C C
+}
+
+// K3 创建 Key3
+func K3[A, B, C comparable](a A, b B, c C) Key3[A, B, C] {
+	return Key3[A, B, C]{A: a, B: b, C: c}
+}
@@ -0,0 +1,38 @@
+package lru
+
+import "testing"
+
+func TestKey2(t *testing.T) {
+	cache := New[Key2[string, int], string](10, nil, nil)
+	cache.Put(K2("tenant-a", 1), "a1")
+	cache.Put(K2("tenant-b", 1), "b1")
+
+	// 拼接字符串 "a:b" + "c" 与 "a" + "b:c" 会冲突，Key2 不会
+	cache.Put(K2("x:y", 0), "xy")
+	if _, ok := cache.Get(K2("x", 0)); ok {
+		panic("collision")
+	}
+	if value, ok := cache.Get(K2("tenant-a", 1)); !ok || value != "a1" {
+		panic(value)
+	}
+	cache.RemoveIf(func(key Key2[string, int]) bool { return key.A == "tenant-b" })
+	if cache.Number() != 2 {
+		panic(cache.AllKeys())
+	}
+}
+
+func TestKey3(t *testing.T) {
+	cache := New[Key3[string, int, bool], int](10, nil, nil)
+	cache.Put(K3("a", 1, true), 1)
+	if _, ok := cache.Get(K3("a", 1, false)); ok {
+		panic("wrong key")
+	}
+	if value, _ := cache.Get(K3("a", 1, true)); value != 1 {
+		panic(value)
+	}
+
+	var r refreshRegistry[Key3[string, int, bool]]
+	if !r.acquire(K3("a", 1, true), 0) || r.acquire(K3("a", 1, true), 0) {
+		panic("registry")
+	}
+}
@@ -0,0 +1,75 @@
+package lru
+
+import "sync"
+
+// KeyedCaches 为每个租户维护一个独立的 LRU 缓存，租户数目本身也由一个 LRU 限制
+// 租户被淘汰时其整个缓存被丢弃，并依次执行租户失效回调和该租户缓存的 RemoveAll
+type KeyedCaches[T comparable, K comparable, V interface{}] struct {
+	lock     sync.Mutex // 保证同一租户只创建一次缓存
+	tenants  *Cache[T, *Cache[K, V]]
+	newCache func(tenant T) *Cache[K, V]
+}
+
+// NewKeyedCaches 创建多租户缓存
+// maxTenants 最多同时保留的租户数目
+// newCache 为新租户创建缓存，各租户可以有不同的大小和回调
+// tenantExpireCallback 租户被淘汰或移除时的回调，可以为空
+func NewKeyedCaches[T comparable, K comparable, V interface{}](maxTenants int, newCache func(tenant T) *Cache[K, V],
+	tenantExpireCallback func(tenant T, cache *Cache[K, V])) *KeyedCaches[T, K, V] {
+	return &KeyedCaches[T, K, V]{
+		tenants: New[T, *Cache[K, V]](maxTenants, func(tenant T, cache *Cache[K, V]) {
+			if tenantExpireCallback != nil {
+				tenantExpireCallback(tenant, cache)
+			}
+			cache.RemoveAll()
+		}, nil),
+		newCache: newCache,
+	}
+}
+
+// Tenant 返回租户的缓存，不存在时创建
+func (kc *KeyedCaches[T, K, V]) Tenant(tenant T) *Cache[K, V] {
+	if cache, ok := kc.tenants.Get(tenant); ok {
+		return cache
+	}
+
+	kc.lock.Lock()
+	defer kc.lock.Unlock()
+	if cache, ok := kc.tenants.Get(tenant); ok {
+		return cache
+	}
+	cache := kc.newCache(tenant)
+	kc.tenants.Put(tenant, cache)
+	return cache
+}
+
+// Put 写入租户的 KV 对
+func (kc *KeyedCaches[T, K, V]) Put(tenant T, key K, value V) {
+	kc.Tenant(tenant).Put(key, value)
+}
+
+// Get 获取租户的 KV 对，租户不存在时不会创建
+func (kc *KeyedCaches[T, K, V]) Get(tenant T, key K) (value V, ok bool) {
+	cache, ok := kc.tenants.Get(tenant)
+	if !ok {
+		return value, false
+	}
+	return cache.Get(key)
+}
+
+// Remove 移除租户的 KV 对
+func (kc *KeyedCaches[T, K, V]) Remove(tenant T, key K) {
+	if cache, ok := kc.tenants.GetNoMove(tenant); ok {
+		cache.Remove(key)
+	}
+}
+
+// RemoveTenant 移除整个租户，执行租户失效回调
+func (kc *KeyedCaches[T, K, V]) RemoveTenant(tenant T) {
+	kc.tenants.Remove(tenant)
+}
+
+// Tenants 按照访问先后返回全部租户
+func (kc *KeyedCaches[T, K, V]) Tenants() []T {
+	return kc.tenants.AllKeys()
+}
@@ -0,0 +1,39 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyedCaches(t *testing.T) {
+	var expired []string
+	kc := NewKeyedCaches[string, int, int](2, func(tenant string) *Cache[int, int] {
+		return New[int, int](3, nil, nil)
+	}, func(tenant string, cache *Cache[int, int]) {
+		expired = append(expired, tenant)
+	})
+
+	for i := 0; i < 5; i++ {
+		kc.Put("a", i, i)
+	}
+	kc.Put("b", 1, 1)
+	if kc.Tenant("a").Number() != 3 {
+		panic(kc.Tenant("a").Number())
+	}
+
+	kc.Put("c", 1, 1) // b 被淘汰
+	if !reflect.DeepEqual(expired, []string{"b"}) {
+		panic(expired)
+	}
+	if _, ok := kc.Get("b", 1); ok {
+		panic("b expired")
+	}
+	if value, ok := kc.Get("a", 4); !ok || value != 4 {
+		panic(value)
+	}
+
+	kc.RemoveTenant("a")
+	if !reflect.DeepEqual(kc.Tenants(), []string{"c"}) {
+		panic(kc.Tenants())
+	}
+}
@@ -0,0 +1,51 @@
+package lru
+
+import "sync"
+
+// keyLocker 按 key 加锁，不同 key 之间互不阻塞
+// 每个 key 的锁带引用计数，无人持有或等待时从 map 中删除，避免 map 无限增长
+type keyLocker[K comparable] struct {
+	lock  sync.Mutex
+	locks map[K]*keyLock
+}
+
+type keyLock struct {
+	mu  sync.Mutex
+	ref int // 持有和等待该锁的数目
+}
+
+func (kl *keyLocker[K]) lockKey(key K) func() {
+	kl.lock.Lock()
+	if kl.locks == nil {
+		kl.locks = map[K]*keyLock{}
+	}
+	l, ok := kl.locks[key]
+	if !ok {
+		l = &keyLock{}
+		kl.locks[key] = l
+	}
+	l.ref++
+	kl.lock.Unlock()
+
+	l.mu.Lock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Unlock()
+			kl.lock.Lock()
+			l.ref--
+			if l.ref == 0 {
+				delete(kl.locks, key)
+			}
+			kl.lock.Unlock()
+		})
+	}
+}
+
+// LockKey 锁住 key 并返回解锁函数，解锁函数重复调用无副作用
+// 用于在缓存之外按 key 串行化外部操作，例如重建某个代价高昂的 value，不同 key 之间互不阻塞。
+// 该锁与缓存自身的读写锁无关，持有期间仍可调用 Get/Put 等方法
+func (c *Cache[K, V]) LockKey(key K) func() {
+	return c.keyLocks.lockKey(key)
+}
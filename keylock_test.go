@@ -0,0 +1,39 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCache_LockKey(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := cache.LockKey(1)
+			defer unlock()
+			value, _ := cache.Get(1)
+			cache.Put(1, value+1)
+		}()
+	}
+	wg.Wait()
+
+	value, _ := cache.Get(1)
+	if value != 100 {
+		panic(value)
+	}
+	if len(cache.keyLocks.locks) != 0 {
+		panic(cache.keyLocks.locks)
+	}
+}
+
+func TestCache_LockKey2(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	unlock1 := cache.LockKey(1)
+	unlock2 := cache.LockKey(2) // 不同 key 不会阻塞
+	unlock1()
+	unlock1()
+	unlock2()
+}
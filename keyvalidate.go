@@ -0,0 +1,36 @@
+package lru
+
+import "fmt"
+
+// InvalidKeyError key 未通过 WithKeyValidator 的校验时 TryPut 返回的错误，Err 为校验函数返回的错误
+type InvalidKeyError struct {
+	Err error
+}
+
+func (e *InvalidKeyError) Error() string {
+	return fmt.Sprintf("lru: invalid key: %v", e.Err)
+}
+
+func (e *InvalidKeyError) Unwrap() error {
+	return e.Err
+}
+
+// WithKeyValidator 写入前校验 key，用于拒绝过长等异常的 key，保护多方共享的缓存
+// 校验失败的 key 不写入：TryPut 返回 *InvalidKeyError，Put、PutWithTTL 和 Do 静默丢弃，WarmFromChannel 跳过，Alias 返回 false。
+// 校验的是调用方传入的 key，别名在 Alias 时已经校验
+func WithKeyValidator[K comparable, V interface{}](validate func(key K) error) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.keyValidator = validate
+	}
+}
+
+// checkKey 按 WithKeyValidator 校验 key
+func (c *Cache[K, V]) checkKey(key K) error {
+	if c.keyValidator == nil {
+		return nil
+	}
+	if err := c.keyValidator(key); err != nil {
+		return &InvalidKeyError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package lru
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCache_WithKeyValidator(t *testing.T) {
+	errTooLong := errors.New("key too long")
+	cache := New[string, int](10, nil, nil, WithKeyValidator[string, int](func(key string) error {
+		if len(key) > 4 {
+			return errTooLong
+		}
+		return nil
+	}))
+
+	err := cache.TryPut("toolong", 1, 0)
+	var invalid *InvalidKeyError
+	if !errors.As(err, &invalid) || !errors.Is(err, errTooLong) {
+		panic(err)
+	}
+	t.Log(err)
+	cache.Put("longer", 2)
+	if cache.Number() != 0 {
+		panic(cache.AllKeys())
+	}
+
+	if err := cache.TryPut("ok", 1, 0); err != nil {
+		panic(err)
+	}
+	if cache.Alias("toolong", "ok") || !cache.Alias("ok2", "ok") {
+		panic("alias")
+	}
+
+	ch := make(chan Entry[string, int], 2)
+	ch <- NewEntry("abcdef", 1)
+	ch <- NewEntry("abc", 1)
+	close(ch)
+	if n, rejected := cache.WarmFromChannel(ch, 1, nil); n != 1 || rejected == 0 {
+		panic(n)
+	}
+}
@@ -0,0 +1,146 @@
+package lru
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// EventType 事件类型
+type EventType int
+
+const (
+	EventPut      EventType = iota // 写入，包括覆盖已有 key
+	EventRemove                    // 主动移除：Remove、RemoveIf、RemoveAll 等
+	EventEvict                     // 容量不足被淘汰
+	EventExpire                    // 过期后被移除
+	EventAnomaly                   // WithAnomalyDetection 发现访问模式异常，缓存内容没有变化
+	EventExpiring                  // WithExpiryWarning 缓存项即将过期，缓存内容没有变化
+	EventHot                       // WithHotKeyThreshold 缓存项的命中次数达到阈值，缓存内容没有变化
+)
+
+var eventTypeNames = [...]string{"put", "remove", "evict", "expire", "anomaly", "expiring", "hot"}
+
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "unknown"
+	}
+	return eventTypeNames[t]
+}
+
+// Event 缓存变更事件
+type Event[K comparable, V interface{}] struct {
+	Type    EventType
+	Key     K
+	Value   V
+	Anomaly AnomalyKind // EventAnomaly 的异常类型，其他事件为 AnomalyNone
+}
+
+// DeliveryMode 事件投递方式
+type DeliveryMode int
+
+const (
+	// DeliverSync 在触发事件的 goroutine 中、持有缓存锁时同步调用，延迟最低且严格有序。
+	// 监听函数不能调用缓存的方法，否则死锁，适合计数类的轻量监听
+	DeliverSync DeliveryMode = iota
+	// DeliverOrdered 由单个后台 goroutine 按事件发生的顺序投递，适合持久化、复制等依赖顺序的监听
+	DeliverOrdered
+	// DeliverPool 由多个后台 goroutine 并发投递，不保证顺序，适合慢且相互独立的监听
+	DeliverPool
+)
+
+// ListenerConfig 监听配置
+type ListenerConfig struct {
+	Mode      DeliveryMode
+	Workers   int // DeliverPool 的 goroutine 数目，<= 0 时为 runtime.NumCPU()
+	QueueSize int // 异步投递的队列长度，<= 0 时为 1024。队列满时触发事件的写操作阻塞等待
+}
+
+type listener[K comparable, V interface{}] struct {
+	fn     func(Event[K, V])
+	locked func(Unlocked[K, V], Event[K, V]) // AddLockedListener 的监听函数，不为空时代替 fn
+	queue  chan Event[K, V]                  // DeliverSync 时为空
+	wg     sync.WaitGroup
+}
+
+// AddListener 添加事件监听，返回移除函数
+// 移除函数会等待队列中已有的事件投递完毕，重复调用无副作用。
+// 异步投递时，监听函数可以调用缓存的方法，但若同时队列已满，触发事件的写操作持有锁等待队列，会造成死锁，需要留足队列长度
+func (c *Cache[K, V]) AddListener(fn func(Event[K, V]), config ListenerConfig) (remove func()) {
+	l := &listener[K, V]{fn: fn}
+	if config.Mode != DeliverSync {
+		queueSize := config.QueueSize
+		if queueSize <= 0 {
+			queueSize = 1024
+		}
+		workers := 1
+		if config.Mode == DeliverPool {
+			workers = config.Workers
+			if workers <= 0 {
+				workers = runtime.NumCPU()
+			}
+		}
+		l.queue = make(chan Event[K, V], queueSize)
+		l.wg.Add(workers)
+		for i := 0; i < workers; i++ {
+			go c.pprofDo(context.Background(), "listener", func(context.Context) {
+				defer l.wg.Done()
+				for event := range l.queue {
+					l.fn(event)
+				}
+			})
+		}
+	}
+
+	return c.addListener(l)
+}
+
+// AddLockedListener 添加同步监听，fn 在触发事件的 goroutine 中、持有缓存写锁时调用，可以通过 u 读写缓存，
+// 例如某项被淘汰时写入它的替代项。u 上的写操作会同步触发新的事件，fn 需要自行避免无限递归
+func (c *Cache[K, V]) AddLockedListener(fn func(u Unlocked[K, V], event Event[K, V])) (remove func()) {
+	return c.addListener(&listener[K, V]{locked: fn})
+}
+
+func (c *Cache[K, V]) addListener(l *listener[K, V]) (remove func()) {
+	c.lock.Lock()
+	c.listeners = append(c.listeners, l)
+	c.lock.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.lock.Lock()
+			for i, other := range c.listeners {
+				if other == l {
+					c.listeners = append(c.listeners[:i:i], c.listeners[i+1:]...)
+					break
+				}
+			}
+			c.lock.Unlock()
+			if l.queue != nil {
+				close(l.queue)
+				l.wg.Wait()
+			}
+		})
+	}
+}
+
+func (c *Cache[K, V]) notifyUnlock(typ EventType, key K, value V) {
+	if len(c.listeners) == 0 {
+		return
+	}
+	c.dispatchUnlock(Event[K, V]{Type: typ, Key: key, Value: value})
+}
+
+// dispatchUnlock 向全部监听投递事件
+func (c *Cache[K, V]) dispatchUnlock(event Event[K, V]) {
+	for _, l := range c.listeners {
+		if l.locked != nil {
+			l.locked(Unlocked[K, V]{c: c}, event)
+		} else if l.queue == nil {
+			l.fn(event)
+		} else {
+			l.queue <- event
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_AddListener(t *testing.T) {
+	cache := New[int, int](2, nil, nil)
+	var events []string
+	remove := cache.AddListener(func(e Event[int, int]) {
+		events = append(events, e.Type.String())
+	}, ListenerConfig{Mode: DeliverSync})
+
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3) // 淘汰 1
+	cache.Remove(2)
+	cache.PutWithTTL(4, 4, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	_, _ = cache.Get(4)
+	remove()
+	cache.Put(5, 5)
+
+	if !reflect.DeepEqual(events, []string{"put", "put", "put", "evict", "remove", "put", "expire"}) {
+		panic(events)
+	}
+}
+
+func TestCache_AddListener_Ordered(t *testing.T) {
+	cache := New[int, int](100, nil, nil)
+	var keys []int
+	remove := cache.AddListener(func(e Event[int, int]) {
+		keys = append(keys, e.Key)
+		_, _ = cache.GetNoMove(e.Key) // 异步投递时可以调用缓存
+	}, ListenerConfig{Mode: DeliverOrdered})
+	for i := 0; i < 50; i++ {
+		cache.Put(i, i)
+	}
+	remove()
+	remove()
+
+	if len(keys) != 50 || !sort.IntsAreSorted(keys) {
+		panic(keys)
+	}
+}
+
+func TestCache_AddListener_Pool(t *testing.T) {
+	cache := New[int, int](100, nil, nil)
+	var count atomic.Int32
+	var lock sync.Mutex
+	seen := map[int]bool{}
+	remove := cache.AddListener(func(e Event[int, int]) {
+		count.Add(1)
+		lock.Lock()
+		seen[e.Key] = true
+		lock.Unlock()
+	}, ListenerConfig{Mode: DeliverPool, Workers: 4, QueueSize: 8})
+	for i := 0; i < 50; i++ {
+		cache.Put(i, i)
+	}
+	cache.RemoveAll()
+	remove()
+
+	if count.Load() != 100 || len(seen) != 50 {
+		panic(count.Load())
+	}
+}
@@ -2,83 +2,278 @@ package lru
 
 import (
 	"container/list"
+	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type Entry[K comparable, V interface{}] struct {
-	key   K
-	value V
+	hits       uint64 // 命中次数，原子操作，放在首位以保证 32 位平台上 8 字节对齐
+	key        K
+	value      V
+	createTime time.Time     // 写入时间
+	accessTime time.Time     // 最近一次访问时间，GetNoMove 不更新
+	expireAt   time.Time     // 过期时间，零值表示永不过期
+	softAt     time.Time     // 软过期时间，之后 Do 在后台刷新，零值表示没有软过期
+	stale      bool          // 被 Invalidate 标记为失效，等待重新验证
+	refs       int           // GetRef 未释放的引用数
+	deferred   bool          // 移除时仍有引用，失效函数推迟到引用全部释放
+	insertEle  *list.Element // WithStableIterationOrder 写入顺序中的节点
+}
+
+// NewEntry 创建缓存项，用于 WarmFromChannel 等批量写入
+func NewEntry[K comparable, V interface{}](key K, value V) Entry[K, V] {
+	now := time.Now()
+	return Entry[K, V]{key: key, value: value, createTime: now, accessTime: now}
+}
+
+// NewEntryWithTTL 创建 ttl 后过期的缓存项，ttl <= 0 时永不过期
+func NewEntryWithTTL[K comparable, V interface{}](key K, value V, ttl time.Duration) Entry[K, V] {
+	e := NewEntry(key, value)
+	if ttl > 0 {
+		e.expireAt = e.createTime.Add(ttl)
+	}
+	return e
+}
+
+func (e *Entry[K, V]) Key() K {
+	return e.key
+}
+
+func (e *Entry[K, V]) Value() V {
+	return e.value
+}
+
+// ExpireAt 返回过期时间，零值表示永不过期
+func (e *Entry[K, V]) ExpireAt() time.Time {
+	return e.expireAt
+}
+
+// expired 判断 now 时刻是否已过期
+func (e *Entry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
 }
 
 type Cache[K comparable, V interface{}] struct {
 	li             *list.List
 	m              map[K]*list.Element
 	lock           sync.RWMutex
-	expireCallback func(key K, value V)     // 失效回调
-	sizeCal        func(key K, value V) int // key/value 大小计算函数
-	maxSize        int
-	curSize        int // size 并不是 len(m)，而是经过 sizeCal 计算累加值
+	expireCallback func(key K, value V) // 实际执行的失效回调，可能经过限速包装
+	curSize        int                  // size 并不是 len(m)，而是经过 sizeCal 计算累加值
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions uint64    // 因容量不足淘汰的次数，持有写锁时修改
+	lifetime  Histogram // 被淘汰项的存活时长
+	idle      Histogram // 被淘汰项距最近一次访问的时长
+
+	keyLocks keyLocker[K] // LockKey 使用的按 key 锁
+
+	flightLock sync.Mutex
+	flights    map[K]*call[V]     // Do 正在执行的加载
+	negatives  *Cache[K, error]   // Do 的错误缓存，首次需要时创建
+	refreshes  refreshRegistry[K] // Do 正在进行的后台刷新
+	breaker    breaker            // Do 的熔断器
+
+	inflightLimit atomic.Int64           // WithMaxInflightLoads 的上限，Do 持有 flightLock 时读取，因此不放在 options 中读
+	labelName     atomic.Pointer[string] // WithPprofLabels 的缓存名，为空表示未开启，加载函数和后台 goroutine 不持有锁时读取
+
+	adapt  adaptState[K]  // WithAdaptiveCapacity 的状态
+	shadow shadowState[K] // WithShadow 的影子缓存
+	decay  decayState     // WithFrequencyDecay 的状态
+
+	evictLog  evictLog[K, V]  // WithEvictionLog 的记录
+	churn     churnState      // WithChurnWindow 的统计
+	anomalies anomalyState[K] // WithAnomalyDetection 当前窗口的统计
+
+	wal          *wal         // 预写日志，为空表示未开启
+	persist      *persister   // WithPersistence 的自动持久化，为空表示未开启
+	lastSnapshot atomic.Int64 // 最近一次成功写入快照的时间，UnixNano。SaveToFile 只持有读锁，因此用原子操作
+
+	listeners []*listener[K, V]
+
+	expireLimiter *expireLimiter[K, V] // WithExpireRateLimit 时不为空
+
+	quotaSizes map[string]int // WithQuota 时每个标签占用的大小
+
+	dependents map[K]map[K]struct{} // parent -> 依赖它的 child
+	dependsOn  map[K]map[K]struct{} // child -> 它依赖的 parent
+
+	aliases   map[K]K              // alias -> primary
+	aliasesOf map[K]map[K]struct{} // primary -> 它的 alias
+
+	valueTypes map[K]reflect.Type // PutTyped 记录的类型
+	freed      chan struct{}      // PutWait 等待空间时创建，缓存变小时关闭
+	victims    victimCache[K, V]  // WithVictimCache 的受害者缓存
+	incr       *incrState[K]      // SaveIncremental 的状态，为空表示还没有保存过
+	warner     *expiryWarner[K]   // WithExpiryWarning 的状态，开启时创建
+	warnClosed bool               // Close 之后不再安排过期预警
+	reaper     *deadlineReaper[K] // WithDeadlineReaper 的后台移除，第一次 PutUntil 时创建
+	reapClosed bool               // Close 之后不再主动移除 PutUntil 的项
+	insertion  *list.List         // WithStableIterationOrder 的写入顺序，list<*Entry>
+	reserved   int                // Reserve 预留的空间
+	replaying  bool               // 加载快照或重放日志期间不按容量淘汰，结束后统一淘汰
+
+	options[K, V]
 }
 
 // New 创建一个 LRU 缓存
 // maxSize 最大缓存大小。缓存大小不是缓存项的数目，而是由 sizeCal 函数计算每项缓存的大小之和
 // expireCallback 缓存失效回调，可以为空
-// sizeCal 缓存项大小计算，可以为空，此时函数返回 1
-func New[K comparable, V interface{}](maxSize int, expireCallback func(key K, value V), sizeCal func(key K, value V) int) *Cache[K, V] {
-	if expireCallback == nil {
-		expireCallback = func(key K, value V) {}
+// sizeCal 缓存项大小计算，可以为空，此时函数返回 1。返回 0 或负数时的处理见 WithSizePolicy
+// opts 其他可选配置，见 With 开头的函数
+func New[K comparable, V interface{}](maxSize int, expireCallback func(key K, value V), sizeCal func(key K, value V) int, opts ...Option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		li: list.New(), // list<*Entry>
+		m:  map[K]*list.Element{},
 	}
-	if sizeCal == nil {
-		sizeCal = func(key K, value V) int { return 1 }
+	c.maxSize = maxSize
+	c.onExpire = expireCallback
+	c.sizeCal = sizeCal
+	for _, opt := range opts {
+		opt(&c.options)
 	}
-
-	return &Cache[K, V]{
-		li:             list.New(), // list<*Entry>
-		m:              map[K]*list.Element{},
-		expireCallback: expireCallback,
-		sizeCal:        sizeCal,
-		maxSize:        maxSize,
+	c.initOptionsUnlock()
+	if c.persistPath != "" {
+		c.startPersistence()
 	}
+	return c
 }
 
 func (c *Cache[K, V]) Put(key K, value V) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	_ = c.tryPutUnlock(key, value, 0)
+}
+
+// PutWithTTL 写入 KV 对，ttl 后过期。ttl <= 0 时永不过期，等同于 Put
+// 过期项惰性删除：Get 时发现过期才会移除并执行失效函数，在此之前仍占用缓存大小。
+// opts 可以设置 SoftTTL
+func (c *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration, opts ...PutOption) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_ = c.tryPutUnlock(key, value, ttl, opts...)
+}
+
+// tryPutUnlock 校验大小后写入，ttl <= 0 时由 WithExpiry 决定过期时间
+func (c *Cache[K, V]) tryPutUnlock(key K, value V, ttl time.Duration, opts ...PutOption) error {
+	return c.tryPutAtUnlock(key, value, ttl, time.Time{}, opts...)
+}
+
+// tryPutAtUnlock deadline 非零时在 deadline 过期，否则按 ttl 决定过期时间
+func (c *Cache[K, V]) tryPutAtUnlock(key K, value V, ttl time.Duration, deadline time.Time, opts ...PutOption) error {
+	if err := c.checkKey(key); err != nil {
+		return err
+	}
+	key = c.resolveUnlock(key)
+	if err := c.checkSize(key, value); err != nil {
+		return err
+	}
+	c.adaptUnlock()
+	c.decayUnlock()
+	c.churnRollUnlock()
+	if deadline.IsZero() && ttl <= 0 && c.expiry != nil {
+		ttl = c.expiry(key, value)
+	}
+	expireAt := deadline
+	if expireAt.IsZero() && ttl > 0 {
+		expireAt = time.Now().Add(ttl)
+	}
+	if !c.skipEqualUnlock(key, value, expireAt) {
+		c.putUnlock(key, value, expireAt)
+	}
+	c.softExpireUnlock(key, opts)
+	return nil
+}
+
+func (c *Cache[K, V]) putUnlock(key K, value V, expireAt time.Time) {
+	now := time.Now()
+	c.churn.puts++
+	delete(c.valueTypes, key)
+	c.victimRemoveUnlock(key)
 	ele, ok := c.m[key]
 	if ok {
-		c.curSize -= c.sizeCal(key, ele.Value.(*Entry[K, V]).value)
+		c.churn.replaces++
+		c.accountUnlock(key, -c.sizeOf(key, ele.Value.(*Entry[K, V]).value))
 		ele.Value.(*Entry[K, V]).value = value
-		c.curSize += c.sizeCal(key, value)
+		ele.Value.(*Entry[K, V]).accessTime = now
+		ele.Value.(*Entry[K, V]).expireAt = expireAt
+		ele.Value.(*Entry[K, V]).softAt = time.Time{}
+		ele.Value.(*Entry[K, V]).stale = false
+		c.accountUnlock(key, c.sizeOf(key, value))
 		c.li.MoveToFront(ele)
 	} else {
-		ele = c.li.PushFront(&Entry[K, V]{key: key, value: value})
+		ele = c.li.PushFront(&Entry[K, V]{key: key, value: value, createTime: now, accessTime: now, expireAt: expireAt})
 		c.m[key] = ele
-		c.curSize += c.sizeCal(key, value)
+		c.insertOrderUnlock(ele.Value.(*Entry[K, V]))
+		if c.adapt.ghost != nil {
+			c.adapt.ghost.remove(key)
+		}
+		c.accountUnlock(key, c.sizeOf(key, value))
 	}
+	c.shadowPutUnlock(key, value)
+	c.logUnlock(opPut, key, value, expireAt)
+	c.notifyUnlock(EventPut, key, value)
+	c.enforceQuotaUnlock(key)
 	c.expireUnlock()
 }
 
 func (c *Cache[K, V]) Get(key K) (value V, ok bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	return c.getUnlock(key)
+}
+
+func (c *Cache[K, V]) getUnlock(key K) (value V, ok bool) {
+	key = c.resolveUnlock(key)
+	c.adaptUnlock()
+	c.decayUnlock()
+	c.churnRollUnlock()
+	c.shadowGetUnlock(key)
+	c.anomalyRollUnlock()
 	ele, ok := c.m[key]
 	if !ok {
+		if ele, ok = c.victimGetUnlock(key); !ok {
+			c.miss()
+			c.anomalyMissUnlock(key)
+			c.ghostMissUnlock(key)
+			return value, false
+		}
+	}
+	if ele.Value.(*Entry[K, V]).expired(time.Now()) {
+		c.removeUnlock(key, EventExpire)
+		c.miss()
+		c.anomalyMissUnlock(key)
+		return value, false
+	}
+	if ele.Value.(*Entry[K, V]).stale {
+		c.miss()
+		c.anomalyMissUnlock(key)
 		return value, false
 	}
-	c.li.MoveToFront(ele)
+	c.hit()
+	hits := atomic.AddUint64(&ele.Value.(*Entry[K, V]).hits, 1)
+	ele.Value.(*Entry[K, V]).accessTime = time.Now()
+	c.promoteUnlock(ele, hits)
+	c.hotUnlock(ele.Value.(*Entry[K, V]), hits)
 	return ele.Value.(*Entry[K, V]).value, true
 }
 
 // GetNoMove 类似 Get 但是不会将命中的 KV 对移动到头部
 // 如果获取元素操作都调用 GetNoMove，LRU 将退化为 FIFO
 // GetNoMove 优势在于性能比 Get 高
+// 命中已过期的项时返回 false，但不会移除它
 func (c *Cache[K, V]) GetNoMove(key K) (value V, ok bool) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	ele, ok := c.m[key]
-	if !ok {
+	ele, ok := c.m[c.resolveUnlock(key)]
+	if !ok || ele.Value.(*Entry[K, V]).stale || ele.Value.(*Entry[K, V]).expired(time.Now()) {
+		c.miss()
 		return value, false
 	}
+	c.hit()
+	atomic.AddUint64(&ele.Value.(*Entry[K, V]).hits, 1)
 	return ele.Value.(*Entry[K, V]).value, true
 }
 
@@ -96,42 +291,109 @@ func (c *Cache[K, V]) LeastRecentlyUsed() (*Entry[K, V], bool) {
 	return nil, false
 }
 
-// AllKeys 按照访问先后获取全部 key
+// AllKeys 按照访问先后获取全部 key，WithStableIterationOrder 时按写入先后
 func (c *Cache[K, V]) AllKeys() []K {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
 	ks := make([]K, 0, c.li.Len())
+	c.rangeUnlock(func(e *Entry[K, V]) bool {
+		ks = append(ks, e.key)
+		return true
+	})
+	return ks
+}
 
-	cur := c.li.Front()
-	for cur != nil {
-		ks = append(ks, cur.Value.(*Entry[K, V]).key)
-		cur = cur.Next()
-	}
+// EvictionOrder 返回当前状态下因容量不足被淘汰的先后顺序，第一个最先被淘汰
+// 顺序是确定的：不在最短驻留保护期内的项按最近最少使用排在前面，受保护的项按同样的规则排在最后。
+// 顺序由链表位置决定，不存在并列。开启 WithPromotionSampling 时 Get 每 n 次命中才把项移到最前，
+// 其余命中不改变顺序，结果反映的是抽样后的位置而不是最后一次访问的先后。配额淘汰只在单个标签内按此顺序进行，
+// 过期项惰性删除，不体现在结果中。开启 WithCostInflation 时实际淘汰会在尾部窗口内按权重挑选，结果只是近似
+func (c *Cache[K, V]) EvictionOrder() []K {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 
-	return ks
+	now := time.Now()
+	ks := make([]K, 0, c.li.Len())
+	var protected []K
+	for cur := c.li.Back(); cur != nil; cur = cur.Prev() {
+		e := cur.Value.(*Entry[K, V])
+		if c.protected(e, now) {
+			protected = append(protected, e.key)
+		} else {
+			ks = append(ks, e.key)
+		}
+	}
+	return append(ks, protected...)
 }
 
-// Scan 按照访问先后遍历所有 KV 对，consumer 返回 bool 指示扫描是否继续
-// 扫描不会修改访问先后顺序
+// Scan 按照访问先后遍历所有 KV 对，WithStableIterationOrder 时按写入先后，consumer 返回 bool 指示扫描是否继续
+// 扫描不会修改访问先后顺序。扫描期间持有读锁，consumer 中调用 Remove 等写方法会死锁，边扫描边移除使用 ScanAndRemove
 func (c *Cache[K, V]) Scan(consumer func(K, V) bool) {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
 
-	element := c.li.Front()
-	for element != nil {
+	c.rangeUnlock(func(e *Entry[K, V]) bool {
+		return consumer(e.key, e.value)
+	})
+}
+
+// ScanMutate 按照访问先后遍历所有 KV 对，并可以原地修改 value
+// mutate 返回新的 value、是否修改以及扫描是否继续。修改会重新计算大小，但不会修改访问先后顺序和过期时间，
+// 扫描结束后若超出容量则按 LRU 淘汰。扫描期间持有写锁，mutate 中不能调用缓存的方法
+func (c *Cache[K, V]) ScanMutate(mutate func(key K, value V) (newValue V, changed bool, cont bool)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var changedKeys []K
+	for element := c.li.Front(); element != nil; element = element.Next() {
+		e := element.Value.(*Entry[K, V])
+		newValue, changed, cont := mutate(e.key, e.value)
+		if changed {
+			c.accountUnlock(e.key, c.sizeOf(e.key, newValue)-c.sizeOf(e.key, e.value))
+			e.value = newValue
+			c.logUnlock(opPut, e.key, e.value, e.expireAt)
+			c.notifyUnlock(EventPut, e.key, e.value)
+			changedKeys = append(changedKeys, e.key)
+		}
+		if !cont {
+			break
+		}
+	}
+	for _, key := range changedKeys {
+		c.enforceQuotaUnlock(key)
+	}
+	c.expireUnlock()
+}
+
+// ScanAndRemove 按照访问先后遍历所有 KV 对，consumer 返回是否移除当前项以及扫描是否继续
+// 被选中的项在扫描结束后依次移除，执行失效函数，与 Remove 相同。扫描期间持有写锁，consumer 中不能调用缓存的方法
+func (c *Cache[K, V]) ScanAndRemove(consumer func(key K, value V) (remove bool, cont bool)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var keys []K
+	for element := c.li.Front(); element != nil; element = element.Next() {
 		e := element.Value.(*Entry[K, V])
-		if !consumer(e.key, e.value) {
+		remove, cont := consumer(e.key, e.value)
+		if remove {
+			keys = append(keys, e.key)
+		}
+		if !cont {
 			break
 		}
-		element = element.Next()
+	}
+	for _, key := range keys { // 移除可能级联移除其他项，因此扫描结束后再移除
+		c.removeUnlock(key, EventRemove)
 	}
 }
 
 func (c *Cache[K, V]) Remove(key K) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	c.removeUnlock(key)
+	key = c.resolveUnlock(key)
+	c.removeUnlock(key, EventRemove)
+	c.victimRemoveUnlock(key)
 }
 
 // RemoveIf 按照条件移除 KV，不会修改扫描先后顺序
@@ -139,18 +401,8 @@ func (c *Cache[K, V]) RemoveIf(remove func(K) bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	cur := c.li.Front()
-	var next *list.Element
-	for cur != nil {
-		next = cur.Next() // 提前记录 next，因为 cur 可能被移除
-		key := cur.Value.(*Entry[K, V]).key
-		if remove(key) {
-			delete(c.m, key)
-			c.li.Remove(cur)
-			c.curSize -= c.sizeCal(key, cur.Value.(*Entry[K, V]).value)
-			c.expireCallback(key, cur.Value.(*Entry[K, V]).value)
-		}
-		cur = next // 注意不能用 cur = cur.next()
+	for _, key := range c.matchUnlock(remove) {
+		c.removeUnlock(key, EventRemove)
 	}
 }
 
@@ -159,63 +411,175 @@ func (c *Cache[K, V]) RemoveIfNoExpire(remove func(K) bool) {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
-	cur := c.li.Front()
-	var next *list.Element
-	for cur != nil {
-		next = cur.Next() // 提前记录 next，因为 cur 可能被移除
-		key := cur.Value.(*Entry[K, V]).key
-		if remove(key) {
-			delete(c.m, key)
-			c.li.Remove(cur)
-			c.curSize -= c.sizeCal(key, cur.Value.(*Entry[K, V]).value)
+	for _, key := range c.matchUnlock(remove) {
+		c.removeNoExpireUnlock(key)
+	}
+}
+
+// matchUnlock 按照访问先后返回满足条件的 key
+// 先收集再移除，因为移除一项可能级联移除其他项，边遍历边移除会跳过元素
+func (c *Cache[K, V]) matchUnlock(match func(K) bool) []K {
+	var keys []K
+	for cur := c.li.Front(); cur != nil; cur = cur.Next() {
+		if key := cur.Value.(*Entry[K, V]).key; match(key) {
+			keys = append(keys, key)
 		}
-		cur = next // 注意不能用 cur = cur.next()
 	}
+	return keys
 }
 
-func (c *Cache[K, V]) removeUnlock(key K) {
+// removeUnlock 移除 key 并执行失效函数，reason 为移除原因
+func (c *Cache[K, V]) removeUnlock(key K, reason EventType) {
 	ele, ok := c.m[key]
 	if ok {
-		delete(c.m, key)
-		c.li.Remove(ele)
-		c.curSize -= c.sizeCal(key, ele.Value.(*Entry[K, V]).value)
-		c.expireCallback(key, ele.Value.(*Entry[K, V]).value)
+		c.removeElementUnlock(ele, reason, true)
+	}
+}
+
+// removeNoExpireUnlock 移除 key，不执行失效函数
+func (c *Cache[K, V]) removeNoExpireUnlock(key K) {
+	ele, ok := c.m[key]
+	if ok {
+		c.removeElementUnlock(ele, EventRemove, false)
+	}
+}
+
+// removeElementUnlock 移除 ele，expire 指示是否执行失效函数
+// 只有主动移除（reason 为 EventRemove）会写预写日志，淘汰和过期在重放时会重新发生。
+// 依赖于 ele 的项随后被级联移除
+func (c *Cache[K, V]) removeElementUnlock(ele *list.Element, reason EventType, expire bool) {
+	e := ele.Value.(*Entry[K, V])
+	delete(c.m, e.key)
+	delete(c.valueTypes, e.key)
+	c.li.Remove(ele)
+	c.removeOrderUnlock(e)
+	c.accountUnlock(e.key, -c.sizeOf(e.key, e.value))
+	c.logRemoveUnlock(e.key) // 淘汰和过期也写日志，否则重放时按不同的访问先后淘汰
+	if reason != EventEvict {
+		c.shadowRemoveUnlock(e.key)
+	}
+	if reason == EventEvict || reason == EventExpire {
+		c.recordEvictUnlock(e, reason)
+	}
+	if expire && reason == EventEvict && c.victimSize > 0 {
+		c.victimAddUnlock(e)
+	} else if expire {
+		c.expireEntryUnlock(e)
 	}
+	c.notifyUnlock(reason, e.key, e.value)
+	if c.metrics != nil {
+		c.metrics.Remove(reason)
+	}
+	c.dropAliasesUnlock(e.key)
+	c.cascadeUnlock(e.key, expire)
 }
 
 func (c *Cache[K, V]) RemoveAll() {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 	for k, ele := range c.m {
-		c.expireCallback(k, ele.Value.(*Entry[K, V]).value)
+		c.expireEntryUnlock(ele.Value.(*Entry[K, V]))
+		c.notifyUnlock(EventRemove, k, ele.Value.(*Entry[K, V]).value)
+		if c.metrics != nil {
+			c.metrics.Remove(EventRemove)
+		}
 	}
-	c.li = list.New()
-	c.m = map[K]*list.Element{}
-	c.curSize = 0
+	c.resetVictimsUnlock(true)
+	c.logClearUnlock()
+	c.clearUnlock()
 }
 
 // RemoveAllNoExpire 不执行失效函数
 func (c *Cache[K, V]) RemoveAllNoExpire() {
 	c.lock.Lock()
 	defer c.lock.Unlock()
+	for k, ele := range c.m {
+		c.notifyUnlock(EventRemove, k, ele.Value.(*Entry[K, V]).value)
+		if c.metrics != nil {
+			c.metrics.Remove(EventRemove)
+		}
+	}
+	c.logClearUnlock()
+	c.clearUnlock()
+}
+
+// clearUnlock 清空缓存，不执行失效函数也不通知监听
+func (c *Cache[K, V]) clearUnlock() {
 	c.li = list.New()
 	c.m = map[K]*list.Element{}
+	if c.insertion != nil {
+		c.insertion = list.New()
+	}
 	c.curSize = 0
+	c.quotaSizes = nil
+	c.dependents = nil
+	c.dependsOn = nil
+	c.shadow.cache = nil
+	c.aliases = nil
+	c.aliasesOf = nil
+	c.valueTypes = nil
+	c.resetVictimsUnlock(false)
+	c.signalFreedUnlock()
+	c.sampleUnlock()
 }
 
-// Size 返回内存占用
+// Size 返回内存占用。持有锁的回调中使用 Unlocked 的 Size
 func (c *Cache[K, V]) Size() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.curSize
 }
 
-// Number 返回元素个数
+// Number 返回元素个数。持有锁的回调中使用 Unlocked 的 Number
 func (c *Cache[K, V]) Number() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
 	return c.li.Len()
 }
 
+// accountUnlock 累加 key 的大小变化
+func (c *Cache[K, V]) accountUnlock(key K, delta int) {
+	c.curSize += delta
+	if delta < 0 {
+		c.signalFreedUnlock()
+	}
+	if c.quotaLabel != nil {
+		c.accountQuotaUnlock(key, delta)
+	}
+	c.sampleUnlock()
+}
+
 func (c *Cache[K, V]) expireUnlock() {
-	for c.curSize > c.maxSize && c.li.Len() > 0 {
-		back := c.li.Back()
-		c.removeUnlock(back.Value.(*Entry[K, V]).key)
+	if c.replaying {
+		return
 	}
+	now := time.Now()
+	for c.curSize > c.limitUnlock() {
+		victim := c.victimUnlock(now)
+		if victim == nil {
+			return
+		}
+		c.evictUnlock(victim) // 淘汰可能级联移除其他项，每次从尾部重新选择
+	}
+}
+
+// evictUnlock 因容量不足淘汰 ele，并记录统计信息
+func (c *Cache[K, V]) evictUnlock(ele *list.Element) {
+	e := ele.Value.(*Entry[K, V])
+	now := time.Now()
+	c.evictions++
+	c.lifetime.observe(now.Sub(e.createTime))
+	c.idle.observe(now.Sub(e.accessTime))
+	if c.adapt.ghost != nil {
+		c.adapt.ghost.add(e.key, c.sizeOf(e.key, e.value))
+	}
+	c.removeElementUnlock(ele, EventEvict, true)
+}
+
+// Close 停止 WithPersistence 的定期保存并保存最后一次快照，停止 WithExpiryWarning 的预警和 WithDeadlineReaper 的后台移除，然后关闭预写日志
+// 重复调用无副作用。Close 之后缓存仍然可以使用，但不再自动保存，也不再发送过期预警
+func (c *Cache[K, V]) Close() error {
+	c.stopWarner()
+	c.stopReaper()
+	return c.closeStorage()
 }
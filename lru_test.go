@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 )
 
 func TestCache_Get(t *testing.T) {
@@ -202,3 +203,98 @@ func TestCache_LeastRecentlyUsed(t *testing.T) {
 		panic(kv)
 	}
 }
+
+func TestCache_PutWithTTL(t *testing.T) {
+	expired := 0
+	cache := New[int, int](10, func(key int, value int) { expired++ }, nil)
+	cache.PutWithTTL(1, 10, 20*time.Millisecond)
+	cache.PutWithTTL(2, 20, 0)
+
+	if value, ok := cache.Get(1); !ok || value != 10 {
+		panic(value)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := cache.GetNoMove(1); ok {
+		panic("1 expired")
+	}
+	if _, ok := cache.Get(1); ok {
+		panic("1 expired")
+	}
+	if _, ok := cache.Get(2); !ok {
+		panic("2 removed")
+	}
+	if expired != 1 || cache.Number() != 1 {
+		panic(expired)
+	}
+}
+
+func TestCache_ScanMutate(t *testing.T) {
+	cache := New[int, []int](10, nil, func(key int, value []int) int { return len(value) })
+	for i := 0; i < 4; i++ {
+		cache.Put(i, []int{i})
+	}
+	cache.ScanMutate(func(k int, v []int) ([]int, bool, bool) {
+		if k%2 == 0 {
+			return append(v, 0, 0), true, true
+		}
+		return v, false, true
+	})
+	if !reflect.DeepEqual(cache.AllKeys(), []int{3, 2, 1, 0}) {
+		panic(cache.AllKeys())
+	}
+	if cache.Size() != 8 {
+		panic(cache.Size())
+	}
+
+	// 超出容量时淘汰最久未访问的
+	cache.ScanMutate(func(k int, v []int) ([]int, bool, bool) {
+		return append(v, 0, 0), true, k != 2
+	})
+	if !reflect.DeepEqual(cache.AllKeys(), []int{3, 2, 1}) {
+		panic(cache.AllKeys())
+	}
+	if cache.Size() != 9 {
+		panic(cache.Size())
+	}
+}
+
+func TestCache_EvictionOrder(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	cache.Get(1)
+	order := cache.EvictionOrder()
+	if !reflect.DeepEqual(order, []int{0, 2, 3, 4, 1}) {
+		panic(order)
+	}
+
+	cache = New[int, int](3, nil, nil)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	cache.Get(2)
+	order = cache.EvictionOrder()
+	cache.Put(5, 5)
+	cache.Put(6, 6)
+	if !reflect.DeepEqual(cache.AllKeys(), []int{6, 5, order[2]}) {
+		panic(cache.AllKeys())
+	}
+}
+
+func TestCache_ScanAndRemove(t *testing.T) {
+	var expired []int
+	cache := New[int, int](10, func(key int, value int) { expired = append(expired, key) }, nil)
+	for i := 0; i < 6; i++ {
+		cache.Put(i, i)
+	}
+	cache.ScanAndRemove(func(key int, value int) (bool, bool) {
+		return value%2 == 0, key != 1
+	})
+	if !reflect.DeepEqual(cache.AllKeys(), []int{5, 3, 1, 0}) {
+		panic(cache.AllKeys())
+	}
+	if !reflect.DeepEqual(expired, []int{4, 2}) {
+		panic(expired)
+	}
+}
@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// MetricsSink 接收缓存事件推送的指标接收器，用于对接 StatsD、Datadog 等推送式监控
+// 除 Load 外的方法在持有缓存锁时调用，实现必须快速且不阻塞，例如只累加计数或写入带缓冲的 channel
+type MetricsSink interface {
+	Hit()
+	Miss()
+	// Remove 缓存项被移除，reason 为 EventRemove、EventEvict 或 EventExpire
+	Remove(reason EventType)
+	// Load Do、DoAll 的加载函数执行完毕，err 为加载函数返回的错误
+	Load(duration time.Duration, err error)
+}
+
+// WithMetricsSink 设置指标接收器，事件发生时立即推送，不需要轮询 Stats
+func WithMetricsSink[K comparable, V interface{}](sink MetricsSink) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.metrics = sink
+	}
+}
+
+func (c *Cache[K, V]) hit() {
+	c.hits.Add(1)
+	if c.metrics != nil {
+		c.metrics.Hit()
+	}
+}
+
+func (c *Cache[K, V]) miss() {
+	c.misses.Add(1)
+	if c.metrics != nil {
+		c.metrics.Miss()
+	}
+}
+
+// timeLoad 以 pprof 标签 op 执行加载函数并推送耗时
+func (c *Cache[K, V]) timeLoad(ctx context.Context, op string, load func(ctx context.Context) error) {
+	sink := c.metricsSink()
+	c.pprofDo(ctx, op, func(ctx context.Context) {
+		if sink == nil {
+			_ = load(ctx)
+			return
+		}
+		start := time.Now()
+		err := load(ctx)
+		sink.Load(time.Since(start), err)
+	})
+}
+
+// metricsSink 加锁读取指标接收器，Do 不持有缓存锁
+func (c *Cache[K, V]) metricsSink() MetricsSink {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.metrics
+}
@@ -0,0 +1,53 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type countingSink struct {
+	hits, misses int
+	removes      map[EventType]int
+	loads        int
+	loadErrs     int
+}
+
+func (s *countingSink) Hit()  { s.hits++ }
+func (s *countingSink) Miss() { s.misses++ }
+
+func (s *countingSink) Remove(reason EventType) {
+	s.removes[reason]++
+}
+
+func (s *countingSink) Load(duration time.Duration, err error) {
+	s.loads++
+	if err != nil {
+		s.loadErrs++
+	}
+}
+
+func TestCache_WithMetricsSink(t *testing.T) {
+	sink := &countingSink{removes: map[EventType]int{}}
+	cache := New[int, int](2, nil, nil, WithMetricsSink[int, int](sink))
+	cache.Put(1, 1)
+	cache.Get(1)
+	cache.Get(2)
+	cache.Put(2, 2)
+	cache.Put(3, 3) // 淘汰 1
+	cache.Remove(2)
+	cache.PutWithTTL(4, 4, time.Nanosecond)
+	cache.Get(4) // 过期
+
+	cache.Do(5, func() (int, error) { return 5, nil }, 0)
+	cache.Do(6, func() (int, error) { return 0, errors.New("failed") }, 0)
+
+	if sink.hits != 1 || sink.misses != 4 || sink.loads != 2 || sink.loadErrs != 1 {
+		panic(sink)
+	}
+	expect := map[EventType]int{EventEvict: 1, EventRemove: 1, EventExpire: 1}
+	if !reflect.DeepEqual(sink.removes, expect) {
+		panic(sink.removes)
+	}
+}
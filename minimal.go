@@ -0,0 +1,34 @@
+//go:build lru_minimal
+
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// lru_minimal 构建去掉持久化（快照、预写日志、增量快照、WithPersistence、SnapshotView）、
+// 导出（ExportRecords、WriteDOT）和 pprof 标签，核心缓存只依赖基础的标准库，适合嵌入式和 WASM。
+// 以下为核心代码调用的空实现
+
+type wal struct{}
+
+type persister struct{}
+
+type incrState[K comparable] struct{}
+
+func (c *Cache[K, V]) trackUnlock(op byte, key K) {}
+
+func (c *Cache[K, V]) appendWALUnlock(op byte, key K, value V, expireAt time.Time) {}
+
+func (c *Cache[K, V]) startPersistence() {}
+
+func (c *Cache[K, V]) closeStorage() error { return nil }
+
+func (c *Cache[K, V]) walHealthUnlock(report *HealthReport) {}
+
+func checkSnapshotKey(key []byte) error { return nil }
+
+func (c *Cache[K, V]) pprofDo(ctx context.Context, op string, fn func(ctx context.Context)) {
+	fn(ctx)
+}
@@ -0,0 +1,24 @@
+package lru
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestMinimalDeps(t *testing.T) {
+	out, err := exec.Command("go", "list", "-deps", "-tags", "lru_minimal", ".").Output()
+	if err != nil {
+		t.Skip("go list:", err)
+	}
+	deps := map[string]bool{}
+	for _, dep := range strings.Fields(string(out)) {
+		deps[dep] = true
+	}
+	for _, pkg := range []string{"encoding/gob", "encoding/json", "encoding/csv", "encoding/binary", "crypto/aes", "crypto/cipher", "hash/crc32", "runtime/pprof"} {
+		if deps[pkg] {
+			panic(pkg)
+		}
+	}
+	t.Log(len(deps))
+}
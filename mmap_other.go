@@ -0,0 +1,14 @@
+//go:build !unix && !lru_minimal
+
+package lru
+
+import "os"
+
+// mapFile 不支持 mmap 的平台上整体读入文件
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() error { return nil }, nil
+}
@@ -0,0 +1,214 @@
+// Package modeltest 提供 LRU 缓存的参考模型和随机操作测试工具
+// 参考模型用 map + 有序切片实现，逻辑简单到可以一眼看出正确性，
+// RunOps 对被测缓存和模型执行相同的操作序列并比较所有可观察的结果，用于测试新的实现或 fork 出的淘汰策略
+package modeltest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// Subject 被测缓存需要提供的方法，*lru.Cache 满足该接口
+type Subject[K comparable, V interface{}] interface {
+	Put(key K, value V)
+	Get(key K) (V, bool)
+	GetNoMove(key K) (V, bool)
+	Remove(key K)
+	RemoveAll()
+	AllKeys() []K
+	Size() int
+	Number() int
+}
+
+// Model LRU 参考模型，keys[0] 为最近访问的 key
+type Model[K comparable, V interface{}] struct {
+	maxSize int
+	sizeCal func(key K, value V) int
+	keys    []K
+	values  map[K]V
+	size    int
+}
+
+// NewModel 创建参考模型，参数含义与 lru.New 一致
+func NewModel[K comparable, V interface{}](maxSize int, sizeCal func(key K, value V) int) *Model[K, V] {
+	if sizeCal == nil {
+		sizeCal = func(key K, value V) int { return 1 }
+	}
+	return &Model[K, V]{maxSize: maxSize, sizeCal: sizeCal, values: map[K]V{}}
+}
+
+func (m *Model[K, V]) indexOf(key K) int {
+	for i, k := range m.keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model[K, V]) moveToFront(i int) {
+	key := m.keys[i]
+	copy(m.keys[1:i+1], m.keys[:i])
+	m.keys[0] = key
+}
+
+func (m *Model[K, V]) Put(key K, value V) {
+	if i := m.indexOf(key); i >= 0 {
+		m.size -= m.sizeCal(key, m.values[key])
+		m.moveToFront(i)
+	} else {
+		m.keys = append([]K{key}, m.keys...)
+	}
+	m.values[key] = value
+	m.size += m.sizeCal(key, value)
+	for m.size > m.maxSize && len(m.keys) > 0 {
+		m.Remove(m.keys[len(m.keys)-1])
+	}
+}
+
+func (m *Model[K, V]) Get(key K) (V, bool) {
+	i := m.indexOf(key)
+	if i < 0 {
+		var zero V
+		return zero, false
+	}
+	m.moveToFront(i)
+	return m.values[key], true
+}
+
+func (m *Model[K, V]) GetNoMove(key K) (V, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+func (m *Model[K, V]) Remove(key K) {
+	i := m.indexOf(key)
+	if i < 0 {
+		return
+	}
+	m.size -= m.sizeCal(key, m.values[key])
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	delete(m.values, key)
+}
+
+func (m *Model[K, V]) RemoveAll() {
+	m.keys = nil
+	m.values = map[K]V{}
+	m.size = 0
+}
+
+func (m *Model[K, V]) AllKeys() []K {
+	return append(make([]K, 0, len(m.keys)), m.keys...)
+}
+
+func (m *Model[K, V]) Size() int {
+	return m.size
+}
+
+func (m *Model[K, V]) Number() int {
+	return len(m.keys)
+}
+
+// OpKind 操作类型
+type OpKind int
+
+const (
+	OpPut OpKind = iota
+	OpGet
+	OpGetNoMove
+	OpRemove
+	OpRemoveAll
+)
+
+var opNames = [...]string{"Put", "Get", "GetNoMove", "Remove", "RemoveAll"}
+
+func (k OpKind) String() string {
+	if k < 0 || int(k) >= len(opNames) {
+		return fmt.Sprintf("OpKind(%d)", int(k))
+	}
+	return opNames[k]
+}
+
+// Op 一次操作，Value 只对 OpPut 有意义
+type Op[K comparable, V interface{}] struct {
+	Kind  OpKind
+	Key   K
+	Value V
+}
+
+func (op Op[K, V]) String() string {
+	switch op.Kind {
+	case OpPut:
+		return fmt.Sprintf("Put(%v, %v)", op.Key, op.Value)
+	case OpRemoveAll:
+		return "RemoveAll()"
+	default:
+		return fmt.Sprintf("%v(%v)", op.Kind, op.Key)
+	}
+}
+
+// RandomOps 生成 n 个随机操作，key 取值于 [0, keySpace)
+// Put 和 Get 占多数，RemoveAll 很少出现，使缓存大部分时间处于满的状态
+func RandomOps(rng *rand.Rand, n int, keySpace int) []Op[int, int] {
+	ops := make([]Op[int, int], n)
+	for i := range ops {
+		op := Op[int, int]{Key: rng.Intn(keySpace), Value: rng.Intn(100)}
+		switch p := rng.Intn(100); {
+		case p < 45:
+			op.Kind = OpPut
+		case p < 80:
+			op.Kind = OpGet
+		case p < 90:
+			op.Kind = OpGetNoMove
+		case p < 99:
+			op.Kind = OpRemove
+		default:
+			op.Kind = OpRemoveAll
+		}
+		ops[i] = op
+	}
+	return ops
+}
+
+// RunOps 对 subject 和 model 依次执行 ops，每步之后比较返回值、AllKeys、Size 和 Number，
+// 遇到第一个不一致时通过 t.Fatalf 报告出错的步骤和之前的操作
+func RunOps[K comparable, V interface{}](t testing.TB, subject Subject[K, V], model *Model[K, V], ops []Op[K, V]) {
+	t.Helper()
+	for i, op := range ops {
+		var got, want interface{}
+		switch op.Kind {
+		case OpPut:
+			subject.Put(op.Key, op.Value)
+			model.Put(op.Key, op.Value)
+		case OpGet:
+			got, want = pair(subject.Get(op.Key)), pair(model.Get(op.Key))
+		case OpGetNoMove:
+			got, want = pair(subject.GetNoMove(op.Key)), pair(model.GetNoMove(op.Key))
+		case OpRemove:
+			subject.Remove(op.Key)
+			model.Remove(op.Key)
+		case OpRemoveAll:
+			subject.RemoveAll()
+			model.RemoveAll()
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("op %d %v: got %v, want %v; history %v", i, op, got, want, ops[:i])
+		}
+		if got, want := subject.AllKeys(), model.AllKeys(); !(len(got) == 0 && len(want) == 0) && !reflect.DeepEqual(got, want) {
+			t.Fatalf("op %d %v: AllKeys got %v, want %v; history %v", i, op, got, want, ops[:i])
+		}
+		if got, want := subject.Size(), model.Size(); got != want {
+			t.Fatalf("op %d %v: Size got %d, want %d; history %v", i, op, got, want, ops[:i])
+		}
+		if got, want := subject.Number(), model.Number(); got != want {
+			t.Fatalf("op %d %v: Number got %d, want %d; history %v", i, op, got, want, ops[:i])
+		}
+	}
+}
+
+func pair[V interface{}](value V, ok bool) [2]interface{} {
+	return [2]interface{}{value, ok}
+}
@@ -0,0 +1,37 @@
+package modeltest
+
+import (
+	"math/rand"
+	"testing"
+
+	lru "github.com/madokast/LRU"
+)
+
+func TestRunOps(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		RunOps[int, int](t, lru.New[int, int](10, nil, nil), NewModel[int, int](10, nil), RandomOps(rng, 2000, 30))
+	}
+}
+
+func TestRunOps_Size(t *testing.T) {
+	sizeCal := func(key int, value int) int { return 1 + value%7 }
+	for seed := int64(0); seed < 20; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		RunOps[int, int](t, lru.New[int, int](20, nil, sizeCal), NewModel[int, int](20, sizeCal), RandomOps(rng, 2000, 30))
+	}
+}
+
+func TestModel(t *testing.T) {
+	model := NewModel[int, int](2, nil)
+	model.Put(1, 1)
+	model.Put(2, 2)
+	_, _ = model.Get(1)
+	model.Put(3, 3)
+	if _, ok := model.GetNoMove(2); ok {
+		panic(model.AllKeys())
+	}
+	if keys := model.AllKeys(); keys[0] != 3 || keys[1] != 1 {
+		panic(keys)
+	}
+}
@@ -0,0 +1,31 @@
+package lru
+
+import "time"
+
+// 修改操作的类型，用于预写日志、快照和增量快照
+const (
+	opPut byte = iota + 1
+	opRemove
+	opClear
+	opInvalidate
+)
+
+// logUnlock 记录一次修改：标记增量快照的变化，安排过期预警，并追加写入预写日志
+func (c *Cache[K, V]) logUnlock(op byte, key K, value V, expireAt time.Time) {
+	c.trackUnlock(op, key)
+	if op == opPut {
+		c.warnUnlock(key, expireAt)
+	}
+	c.appendWALUnlock(op, key, value, expireAt)
+}
+
+func (c *Cache[K, V]) logRemoveUnlock(key K) {
+	var value V
+	c.logUnlock(opRemove, key, value, time.Time{})
+}
+
+func (c *Cache[K, V]) logClearUnlock() {
+	var key K
+	var value V
+	c.logUnlock(opClear, key, value, time.Time{})
+}
@@ -0,0 +1,200 @@
+package lru
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// options New 的可选配置，嵌入 Cache
+type options[K comparable, V interface{}] struct {
+	maxSize        int
+	onExpire       func(key K, value V)                   // 失效回调
+	onExpireLocked func(u Unlocked[K, V], key K, value V) // WithLockedExpireCallback 的失效回调，不为空时代替 onExpire
+	sizeCal        func(key K, value V) int               // key/value 大小计算函数
+
+	snapshotKey []byte // 快照和预写日志的加密密钥，为空表示不加密
+
+	expirePerSecond int                        // 失效回调每秒最多执行的次数，<= 0 表示不限
+	expireBatch     func(keys []K, values []V) // 超出限制的失效项的批量处理函数
+
+	quotaLabel func(key K) string // 配额标签，为空表示不限制
+	quotaShare float64            // 每个标签最多占用的缓存大小比例
+
+	sizePolicy SizePolicy // sizeCal 返回 <= 0 时的处理策略
+
+	minResidency time.Duration // 写入后免于淘汰的最短时间
+
+	history *sizeHistory // 大小历史，为空表示不记录
+
+	expiry func(key K, value V) time.Duration // 由 KV 计算过期时长，为空表示不过期
+
+	refreshAhead time.Duration // Do 提前刷新的窗口，<= 0 表示不提前刷新
+	maxRefreshes int           // 同时进行的后台刷新上限，<= 0 表示不限
+
+	adaptive *AdaptiveCapacity // 自适应容量配置，为空表示不调整
+
+	shadowSize int // 影子缓存容量，<= 0 表示不开启
+
+	equal func(a, b V) bool // 写入相等的 value 时跳过，为空表示总是写入
+
+	decayInterval time.Duration // 命中次数减半的周期，<= 0 表示不衰减
+
+	evictLogSize   int  // 保留的被移除项数目，<= 0 表示不保留
+	evictLogValues bool // 是否保留被移除项的 value
+
+	breakerFailures int           // Do 熔断前的连续失败次数，<= 0 表示不熔断
+	breakerCooldown time.Duration // 熔断后到试探加载的冷却时间
+
+	validator func(ctx context.Context, key K, value V) (bool, error) // 过期项的校验函数
+
+	maxInflight int // Do 同时加载的不同 key 上限，<= 0 表示不限
+
+	metrics MetricsSink // 指标接收器，为空表示不推送
+
+	inflateWindow int                                 // 按有效权重挑选淘汰项的尾部窗口，<= 1 表示按 LRU 淘汰
+	inflateWeight func(size int, hits uint64) float64 // 有效权重
+
+	churnWindow time.Duration // 变化速率的统计窗口，<= 0 表示不统计
+
+	persistPath     string        // 自动持久化的快照路径，为空表示不开启
+	persistInterval time.Duration // 自动保存的间隔
+
+	pprofLabels bool   // 是否为加载函数和后台 goroutine 加上 pprof 标签
+	pprofName   string // pprof 标签中的缓存名
+
+	keyValidator func(key K) error // 写入前校验 key，为空表示不校验
+	promoteEvery int               // Get 每命中多少次移到最前，<= 1 表示每次
+	anomaly      *AnomalyConfig    // 访问模式异常检测的配置，为空表示不检测
+	victimSize   int               // 受害者缓存的容量，<= 0 表示不开启
+	warnLead     time.Duration     // 过期前多久发送 EventExpiring，<= 0 表示不发送
+	stableOrder  bool              // 是否维护写入顺序索引
+	hotThreshold uint64            // 命中次数达到多少时发送 EventHot，0 表示不发送
+	reapDeadline bool              // PutUntil 的项是否由后台 goroutine 到期移除
+}
+
+// Option New 的可选配置项
+type Option[K comparable, V interface{}] func(o *options[K, V])
+
+// ErrInvalidOption Configure 校验配置失败时返回的错误
+var ErrInvalidOption = errors.New("lru: invalid option")
+
+// WithMaxSize 设置最大缓存大小，用于 Configure 修改运行中缓存的容量
+func WithMaxSize[K comparable, V interface{}](maxSize int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxSize = maxSize
+	}
+}
+
+// WithExpireCallback 设置失效回调，为空表示不回调
+func WithExpireCallback[K comparable, V interface{}](expireCallback func(key K, value V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onExpire = expireCallback
+		o.onExpireLocked = nil
+	}
+}
+
+// WithSizeCal 设置缓存项大小计算函数，为空表示每项大小为 1。Configure 修改后会重新计算所有项的大小
+func WithSizeCal[K comparable, V interface{}](sizeCal func(key K, value V) int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.sizeCal = sizeCal
+	}
+}
+
+// validate 校验配置组合
+func (o *options[K, V]) validate() error {
+	if o.maxSize < 0 {
+		return fmt.Errorf("%w: negative maxSize %d", ErrInvalidOption, o.maxSize)
+	}
+	if o.quotaLabel != nil && (o.quotaShare <= 0 || o.quotaShare > 1) {
+		return fmt.Errorf("%w: quota share %v out of (0, 1]", ErrInvalidOption, o.quotaShare)
+	}
+	if o.minResidency < 0 {
+		return fmt.Errorf("%w: negative min residency %v", ErrInvalidOption, o.minResidency)
+	}
+	if o.adaptive != nil {
+		if err := o.adaptive.validate(); err != nil {
+			return err
+		}
+	}
+	if err := checkSnapshotKey(o.snapshotKey); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidOption, err)
+	}
+	return nil
+}
+
+// initOptionsUnlock 补全默认值并根据配置构造失效回调
+func (c *Cache[K, V]) initOptionsUnlock() {
+	if c.onExpire == nil {
+		c.onExpire = func(key K, value V) {}
+	}
+	if c.sizeCal == nil {
+		c.sizeCal = func(key K, value V) int { return 1 }
+	}
+	c.breaker.configure(c.breakerFailures, c.breakerCooldown)
+	c.inflightLimit.Store(int64(c.maxInflight))
+	c.orderIndexUnlock()
+	if c.pprofLabels {
+		name := c.pprofName
+		c.labelName.Store(&name)
+	} else {
+		c.labelName.Store(nil)
+	}
+	onExpire := c.onExpire
+	if locked := c.onExpireLocked; locked != nil {
+		onExpire = func(key K, value V) { locked(Unlocked[K, V]{c: c}, key, value) }
+	}
+	c.expireCallback = onExpire
+	old := c.expireLimiter
+	c.expireLimiter = nil
+	if c.expirePerSecond > 0 {
+		c.expireLimiter = newExpireLimiter(c.expirePerSecond, onExpire, c.expireBatch)
+		if old != nil {
+			c.expireLimiter.dropped.Store(old.dropped.Load())
+		}
+		c.expireCallback = c.expireLimiter.expire
+	}
+	c.startWarnerUnlock()
+}
+
+// Configure 修改运行中缓存的配置，opts 与 New 相同
+// 新配置校验失败时返回 ErrInvalidOption 且不做任何修改；成功后重新计算所有项的大小，
+// 若超出新的容量或配额则立即淘汰。开启预写日志时不能修改加密密钥
+func (c *Cache[K, V]) Configure(opts ...Option[K, V]) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	o := c.options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := o.validate(); err != nil {
+		return err
+	}
+	if c.wal != nil && !bytes.Equal(o.snapshotKey, c.snapshotKey) {
+		return fmt.Errorf("%w: cannot change snapshot key while WAL is open", ErrInvalidOption)
+	}
+	c.options = o
+	c.initOptionsUnlock()
+
+	// sizeCal、大小策略或配额可能改变，重新累计
+	c.curSize = 0
+	c.quotaSizes = nil
+	labels := map[string]K{}
+	for ele := c.li.Front(); ele != nil; ele = ele.Next() {
+		e := ele.Value.(*Entry[K, V])
+		c.accountUnlock(e.key, c.sizeOf(e.key, e.value))
+		if c.quotaLabel != nil {
+			labels[c.quotaLabel(e.key)] = e.key
+		}
+	}
+	for _, key := range labels {
+		c.enforceQuotaUnlock(key)
+	}
+	c.expireUnlock()
+	c.trimVictimsUnlock()
+	c.signalFreedUnlock()
+	return nil
+}
@@ -0,0 +1,48 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCache_Configure(t *testing.T) {
+	var expired []int
+	cache := New[int, int](10, nil, nil)
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+
+	// 缩容立即淘汰，新的失效回调生效
+	err := cache.Configure(WithMaxSize[int, int](4), WithExpireCallback(func(key int, value int) {
+		expired = append(expired, key)
+	}))
+	if err != nil {
+		panic(err)
+	}
+	if !reflect.DeepEqual(cache.AllKeys(), []int{9, 8, 7, 6}) || len(expired) != 6 {
+		panic(cache.AllKeys())
+	}
+
+	// 修改 sizeCal 后重新计算大小
+	if err = cache.Configure(WithSizeCal(func(key int, value int) int { return 2 })); err != nil {
+		panic(err)
+	}
+	if cache.Size() != 4 || cache.Number() != 2 {
+		panic(cache.Size())
+	}
+
+	// 校验失败时不做修改
+	err = cache.Configure(WithMaxSize[int, int](-1))
+	if !errors.Is(err, ErrInvalidOption) {
+		panic(err)
+	}
+	err = cache.Configure(WithMaxSize[int, int](100), WithQuota[int, int](func(key int) string { return "" }, 2))
+	if !errors.Is(err, ErrInvalidOption) {
+		panic(err)
+	}
+	if cache.maxSize != 4 {
+		panic(cache.maxSize)
+	}
+	t.Log(err)
+}
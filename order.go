@@ -0,0 +1,60 @@
+package lru
+
+import "container/list"
+
+// WithStableIterationOrder 额外维护按首次写入先后排列的索引，AllKeys、Scan 和 ExportRecords 按写入先后遍历，不受访问顺序影响
+// 内容相同时输出稳定，便于导出和比对。覆盖已有 key 不改变其位置，移除后再写入排到最后。
+// 每项多占用一个链表节点。Configure 开启时已有的项按访问先后从旧到新排列
+func WithStableIterationOrder[K comparable, V interface{}]() Option[K, V] {
+	return func(o *options[K, V]) {
+		o.stableOrder = true
+	}
+}
+
+// orderIndexUnlock 按 WithStableIterationOrder 建立或丢弃写入顺序索引
+func (c *Cache[K, V]) orderIndexUnlock() {
+	if !c.stableOrder {
+		c.insertion = nil
+		return
+	}
+	if c.insertion != nil {
+		return
+	}
+	c.insertion = list.New()
+	for ele := c.li.Back(); ele != nil; ele = ele.Prev() {
+		e := ele.Value.(*Entry[K, V])
+		e.insertEle = c.insertion.PushBack(e)
+	}
+}
+
+// insertOrderUnlock 新的缓存项排到写入顺序的最后
+func (c *Cache[K, V]) insertOrderUnlock(e *Entry[K, V]) {
+	if c.insertion != nil {
+		e.insertEle = c.insertion.PushBack(e)
+	}
+}
+
+// removeOrderUnlock 从写入顺序中移除
+func (c *Cache[K, V]) removeOrderUnlock(e *Entry[K, V]) {
+	if c.insertion != nil && e.insertEle != nil {
+		c.insertion.Remove(e.insertEle)
+	}
+	e.insertEle = nil
+}
+
+// rangeUnlock 按 AllKeys 的顺序遍历，fn 返回 false 时停止
+func (c *Cache[K, V]) rangeUnlock(fn func(e *Entry[K, V]) bool) {
+	if c.insertion != nil {
+		for ele := c.insertion.Front(); ele != nil; ele = ele.Next() {
+			if !fn(ele.Value.(*Entry[K, V])) {
+				return
+			}
+		}
+		return
+	}
+	for ele := c.li.Front(); ele != nil; ele = ele.Next() {
+		if !fn(ele.Value.(*Entry[K, V])) {
+			return
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_WithStableIterationOrder(t *testing.T) {
+	cache := New[string, int](3, nil, nil, WithStableIterationOrder[string, int]())
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Get("a")
+	cache.Put("b", 22)
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		panic(keys)
+	}
+	if order := cache.EvictionOrder(); !reflect.DeepEqual(order, []string{"c", "a", "b"}) {
+		panic(order) // 淘汰仍按访问先后
+	}
+
+	cache.Put("d", 4) // 淘汰 c
+	cache.Remove("a")
+	cache.Put("a", 11)
+	var scanned []string
+	cache.Scan(func(key string, value int) bool {
+		scanned = append(scanned, key)
+		return true
+	})
+	t.Log(scanned)
+	if !reflect.DeepEqual(scanned, []string{"b", "d", "a"}) {
+		panic(scanned)
+	}
+
+	// Configure 关闭后恢复按访问先后
+	if err := cache.Configure(func(o *options[string, int]) { o.stableOrder = false }); err != nil {
+		panic(err)
+	}
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"a", "d", "b"}) {
+		panic(keys)
+	}
+	cache.RemoveAll()
+	if err := cache.Configure(WithStableIterationOrder[string, int]()); err != nil {
+		panic(err)
+	}
+	cache.Put("x", 1)
+	cache.Put("y", 2)
+	cache.Get("x")
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"x", "y"}) {
+		panic(keys)
+	}
+}
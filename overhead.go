@@ -0,0 +1,50 @@
+package lru
+
+import (
+	"container/list"
+	"unsafe"
+)
+
+// mapLoadFactor Go map 每个桶 8 个槽位，平均装载因子约 6.5/8
+const mapLoadFactor = 6.5 / 8
+
+// OverheadBytes 估算缓存自身簿记结构占用的内存，不含 value 和 key 所引用的数据（例如 string 的内容）
+// 包括每项的链表节点、Entry 结构体和 map 槽位，以及写入顺序索引、幽灵列表、影子缓存和别名表。
+// 结果按 Go 运行时的数据结构布局计算，忽略分配器的尺寸对齐和 map 扩容期间的旧桶，只用于容量规划
+func (c *Cache[K, V]) OverheadBytes() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	var k K
+	var e Entry[K, V]
+	keySize := int(unsafe.Sizeof(k))
+	ptrSize := int(unsafe.Sizeof(uintptr(0)))
+	elementSize := int(unsafe.Sizeof(list.Element{}))
+
+	overhead := int(unsafe.Sizeof(*c))
+	// 每项：链表节点 + Entry + map 槽位（key + 指针 + 1 字节 tophash）
+	overhead += len(c.m) * (elementSize + int(unsafe.Sizeof(e)))
+	overhead += mapBytes(len(c.m), keySize+ptrSize)
+	if c.insertion != nil {
+		overhead += c.insertion.Len() * elementSize
+	}
+
+	// 幽灵列表只记录 key 和大小，节点的 Value 为接口，ghostEntry 另外分配
+	ghostSize := elementSize + int(unsafe.Sizeof(ghostEntry[K]{}))
+	for _, g := range []*ghostList[K]{c.adapt.ghost, c.shadow.cache} {
+		if g != nil {
+			overhead += g.li.Len()*ghostSize + mapBytes(g.li.Len(), keySize+ptrSize)
+		}
+	}
+	overhead += mapBytes(len(c.aliases), 2*keySize)
+	for _, aliases := range c.aliasesOf {
+		overhead += mapBytes(len(aliases), keySize)
+	}
+	overhead += mapBytes(len(c.aliasesOf), keySize+ptrSize)
+	return overhead
+}
+
+// mapBytes 估算 n 个槽位大小为 slotSize 的 map 占用的内存
+func mapBytes(n, slotSize int) int {
+	return int(float64(n*(slotSize+1)) / mapLoadFactor)
+}
@@ -0,0 +1,26 @@
+package lru
+
+import (
+	"testing"
+)
+
+func TestCache_OverheadBytes(t *testing.T) {
+	cache := New[int, int](1000, nil, nil)
+	empty := cache.OverheadBytes()
+	for i := 0; i < 1000; i++ {
+		cache.Put(i, i)
+	}
+	perEntry := (cache.OverheadBytes() - empty) / 1000
+	t.Log(empty, perEntry)
+	if empty <= 0 || perEntry < 80 || perEntry > 300 {
+		panic(perEntry)
+	}
+
+	shadowed := New[int, int](1000, nil, nil, WithShadow[int, int](2000))
+	for i := 0; i < 1000; i++ {
+		shadowed.Put(i, i)
+	}
+	if shadowed.OverheadBytes() <= cache.OverheadBytes() {
+		panic(shadowed.OverheadBytes())
+	}
+}
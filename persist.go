@@ -0,0 +1,359 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"time"
+)
+
+// formatVersion 快照和预写日志的格式版本
+const formatVersion byte = 2
+
+// 文件头中的标志位
+const (
+	flagEncrypted byte = 1 << iota
+)
+
+const (
+	headerSize       = 6 // magic(4) + version(1) + flags(1)
+	recordHeaderSize = 8 // length(4) + crc32(4)
+	maxRecordSize    = 1 << 30
+)
+
+var (
+	snapshotMagic = [4]byte{'L', 'R', 'U', 'S'}
+	walMagic      = [4]byte{'L', 'R', 'U', 'W'}
+)
+
+var (
+	// ErrCorrupted 持久化数据损坏：文件头错误、校验和不一致、记录不完整或无法解码
+	ErrCorrupted = errors.New("lru: corrupted data")
+	// ErrVersionMismatch 持久化数据的格式版本与当前程序不一致
+	ErrVersionMismatch = errors.New("lru: format version mismatch")
+)
+
+// record 快照和预写日志中的一条记录
+// 使用 gob 编码，K/V 为接口类型时需要事先 gob.Register 具体类型
+type record[K comparable, V interface{}] struct {
+	Op       byte
+	Key      K
+	Value    V
+	ExpireAt time.Time
+	Stale    bool
+}
+
+// writeHeader 写入文件头，aead 不为空时标记为加密
+func writeHeader(w io.Writer, magic [4]byte, aead cipher.AEAD) error {
+	var flags byte
+	if aead != nil {
+		flags |= flagEncrypted
+	}
+	_, err := w.Write([]byte{magic[0], magic[1], magic[2], magic[3], formatVersion, flags})
+	return err
+}
+
+// readHeader 读取并校验文件头，返回标志位。文件为空时返回 io.EOF，不足一个文件头时返回 io.ErrUnexpectedEOF
+func readHeader(r io.Reader, magic [4]byte) (byte, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(header[:4], magic[:]) {
+		return 0, fmt.Errorf("%w: bad magic %q", ErrCorrupted, header[:4])
+	}
+	if header[4] != formatVersion {
+		return 0, fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, header[4], formatVersion)
+	}
+	return header[5], nil
+}
+
+// writeRecord 写入一条记录，格式为 4 字节大端长度 + 4 字节 CRC32 + 负载
+// 负载为 gob 编码，aead 不为空时再经过加密。每条记录使用独立的 gob 编码器，使得日志可以跨进程追加写
+func writeRecord[K comparable, V interface{}](w io.Writer, aead cipher.AEAD, r *record[K, V]) error {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, recordHeaderSize))
+	if err := gob.NewEncoder(&buf).Encode(r); err != nil {
+		return err
+	}
+	b := buf.Bytes()
+	if aead != nil {
+		payload, err := seal(aead, b[recordHeaderSize:])
+		if err != nil {
+			return err
+		}
+		b = append(b[:recordHeaderSize], payload...)
+	}
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)-recordHeaderSize))
+	binary.BigEndian.PutUint32(b[4:8], crc32.ChecksumIEEE(b[recordHeaderSize:]))
+	_, err := w.Write(b)
+	return err
+}
+
+// readRecord 读取一条记录，返回记录占用的字节数。aead 为空表示数据未加密
+// 在记录边界处结束时返回 io.EOF，记录不完整时返回 io.ErrUnexpectedEOF，校验失败时返回 ErrCorrupted，解密失败时返回 ErrDecrypt
+func readRecord[K comparable, V interface{}](r io.Reader, aead cipher.AEAD, rec *record[K, V]) (int, error) {
+	var header [recordHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, err
+	}
+	length := binary.BigEndian.Uint32(header[0:4])
+	if length > maxRecordSize {
+		return 0, fmt.Errorf("%w: record length %d", ErrCorrupted, length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	n := len(header) + len(payload)
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return n, fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+	}
+	if aead != nil {
+		var err error
+		if payload, err = unseal(aead, payload); err != nil {
+			return n, err
+		}
+	}
+	*rec = record[K, V]{}
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(rec); err != nil {
+		return n, fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return n, nil
+}
+
+// readRecords 读取全部记录，返回读取成功的记录以及它们占用的字节数
+func readRecords[K comparable, V interface{}](r io.Reader, aead cipher.AEAD) ([]record[K, V], int64, error) {
+	var records []record[K, V]
+	var offset int64
+	for {
+		var rec record[K, V]
+		n, err := readRecord(r, aead, &rec)
+		if err == io.EOF {
+			return records, offset, nil
+		}
+		if err != nil {
+			return records, offset, err
+		}
+		records = append(records, rec)
+		offset += int64(n)
+	}
+}
+
+// applyUnlock 将记录应用到缓存，不执行失效函数，也不写预写日志
+func (c *Cache[K, V]) applyUnlock(r *record[K, V], now time.Time) {
+	key := c.resolveUnlock(r.Key)
+	switch r.Op {
+	case opPut:
+		if !r.ExpireAt.IsZero() && !now.Before(r.ExpireAt) {
+			c.removeNoExpireUnlock(key)
+			return
+		}
+		c.putUnlock(key, r.Value, r.ExpireAt)
+		if ele, ok := c.m[key]; ok && r.Stale {
+			ele.Value.(*Entry[K, V]).stale = true
+		}
+	case opInvalidate:
+		if ele, ok := c.m[key]; ok {
+			ele.Value.(*Entry[K, V]).stale = true
+		}
+	case opRemove:
+		c.removeNoExpireUnlock(key)
+	case opClear:
+		c.clearUnlock()
+	}
+}
+
+// SaveToFile 将缓存全部内容写入快照文件
+// 先写临时文件再重命名，写入失败不会破坏已有快照。写入期间持有读锁
+func (c *Cache[K, V]) SaveToFile(path string) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.saveUnlock(path)
+}
+
+func (c *Cache[K, V]) saveUnlock(path string) error {
+	aead, err := newAEAD(c.snapshotKey)
+	if err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(file)
+	err = writeHeader(w, snapshotMagic, aead)
+	// 从最久未访问的开始写，加载时访问先后不变
+	for ele := c.li.Back(); ele != nil && err == nil; ele = ele.Prev() {
+		e := ele.Value.(*Entry[K, V])
+		err = writeRecord(w, aead, &record[K, V]{Op: opPut, Key: e.key, Value: e.value, ExpireAt: e.expireAt, Stale: e.stale})
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	c.lastSnapshot.Store(time.Now().UnixNano())
+	return nil
+}
+
+// LoadFromFile 从快照文件加载，加载的 KV 对如同依次 Put 写入，已过期的项被跳过
+// 加载不执行失效函数，但容量不足时仍会按 LRU 淘汰。
+// 快照不完整或校验失败时返回 ErrCorrupted，格式版本不一致时返回 ErrVersionMismatch，无法解密时返回 ErrDecrypt，
+// 这些情况下缓存都不会被修改
+func (c *Cache[K, V]) LoadFromFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	aead, err := newAEAD(c.snapshotKey)
+	if err != nil {
+		return err
+	}
+	records, err := readSnapshot[K, V](bufio.NewReader(file), aead)
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.applyAllUnlock(records)
+	return nil
+}
+
+// readSnapshot 读取快照，文件头标记为未加密时忽略 aead
+func readSnapshot[K comparable, V interface{}](r io.Reader, aead cipher.AEAD) ([]record[K, V], error) {
+	flags, err := readHeader(r, snapshotMagic)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("%w: missing header", ErrCorrupted)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if flags&flagEncrypted == 0 {
+		aead = nil
+	} else if aead == nil {
+		return nil, fmt.Errorf("%w: snapshot is encrypted but no key configured", ErrDecrypt)
+	}
+	records, _, err := readRecords[K, V](r, aead)
+	if err == io.ErrUnexpectedEOF {
+		err = fmt.Errorf("%w: truncated record", ErrCorrupted)
+	}
+	return records, err
+}
+
+func (c *Cache[K, V]) applyAllUnlock(records []record[K, V]) {
+	now := time.Now()
+	c.replaying = true
+	for i := range records {
+		c.applyUnlock(&records[i], now)
+	}
+	c.replaying = false
+	if c.quotaLabel != nil {
+		for label := range c.quotaSizes {
+			c.enforceLabelUnlock(label)
+		}
+	}
+	c.expireUnlock()
+}
+
+// SnapshotEntry 快照中的一项，用于在缓存之外读写快照文件
+type SnapshotEntry[K comparable, V interface{}] struct {
+	Key      K
+	Value    V
+	ExpireAt time.Time // 零值表示永不过期
+	Stale    bool      // 被 Invalidate 标记为失效
+}
+
+// SnapshotHeader 快照或预写日志的文件头
+type SnapshotHeader struct {
+	Version   byte
+	Encrypted bool
+	WAL       bool // 是预写日志而不是快照
+}
+
+// ReadSnapshotHeader 读取快照或预写日志的文件头，格式版本不一致时仍返回文件头以及 ErrVersionMismatch
+func ReadSnapshotHeader(r io.Reader) (SnapshotHeader, error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return SnapshotHeader{}, fmt.Errorf("%w: missing header", ErrCorrupted)
+	}
+	isWAL := bytes.Equal(header[:4], walMagic[:])
+	if !isWAL && !bytes.Equal(header[:4], snapshotMagic[:]) {
+		return SnapshotHeader{}, fmt.Errorf("%w: bad magic %q", ErrCorrupted, header[:4])
+	}
+	h := SnapshotHeader{Version: header[4], Encrypted: header[5]&flagEncrypted != 0, WAL: isWAL}
+	if h.Version != formatVersion {
+		return h, fmt.Errorf("%w: got %d, want %d", ErrVersionMismatch, h.Version, formatVersion)
+	}
+	return h, nil
+}
+
+// ReadSnapshot 读取 SaveToFile 写出的快照，按最久未访问到最近访问的顺序返回全部项
+// key 为 WithSnapshotEncryption 的密钥，快照未加密时忽略
+func ReadSnapshot[K comparable, V interface{}](r io.Reader, key []byte) ([]SnapshotEntry[K, V], error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	records, err := readSnapshot[K, V](bufio.NewReader(r), aead)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]SnapshotEntry[K, V], 0, len(records))
+	for _, rec := range records {
+		if rec.Op == opPut {
+			entries = append(entries, SnapshotEntry[K, V]{Key: rec.Key, Value: rec.Value, ExpireAt: rec.ExpireAt, Stale: rec.Stale})
+		}
+	}
+	return entries, nil
+}
+
+// WriteSnapshot 写出可被 LoadFromFile 加载的快照，entries 按最久未访问到最近访问的顺序排列
+// key 不为空时加密
+func WriteSnapshot[K comparable, V interface{}](w io.Writer, key []byte, entries []SnapshotEntry[K, V]) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	return writeEntries(w, snapshotMagic, aead, entries)
+}
+
+// writeEntries 写出文件头和每一项的写入记录
+func writeEntries[K comparable, V interface{}](w io.Writer, magic [4]byte, aead cipher.AEAD, entries []SnapshotEntry[K, V]) error {
+	bw := bufio.NewWriter(w)
+	if err := writeHeader(bw, magic, aead); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := writeRecord(bw, aead, &record[K, V]{Op: opPut, Key: e.Key, Value: e.Value, ExpireAt: e.ExpireAt, Stale: e.Stale}); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,143 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_SaveToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	cache := New[string, []int](10, nil, nil)
+	cache.Put("a", []int{1})
+	cache.Put("b", []int{2, 3})
+	cache.PutWithTTL("c", []int{4}, time.Millisecond)
+	_, _ = cache.Get("a")
+	if err := cache.SaveToFile(path); err != nil {
+		panic(err)
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	loaded := New[string, []int](10, nil, nil)
+	if err := loaded.LoadFromFile(path); err != nil {
+		panic(err)
+	}
+	if !reflect.DeepEqual(loaded.AllKeys(), []string{"a", "b"}) {
+		panic(loaded.AllKeys())
+	}
+	value, _ := loaded.Get("b")
+	if !reflect.DeepEqual(value, []int{2, 3}) {
+		panic(value)
+	}
+}
+
+func TestCache_LoadFromFile_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	cache := New[int, int](10, nil, nil)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	if err := cache.SaveToFile(path); err != nil {
+		panic(err)
+	}
+	data, _ := os.ReadFile(path)
+
+	// 截断
+	_ = os.WriteFile(path, data[:len(data)-1], 0o644)
+	if err := cache.LoadFromFile(path); !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+
+	// 篡改记录
+	broken := append([]byte(nil), data...)
+	broken[len(broken)-1] ^= 0xff
+	_ = os.WriteFile(path, broken, 0o644)
+	loaded := New[int, int](10, nil, nil)
+	if err := loaded.LoadFromFile(path); !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+	if loaded.Number() != 0 {
+		panic(loaded.Number())
+	}
+
+	// 版本不一致
+	broken = append([]byte(nil), data...)
+	broken[4] = formatVersion + 1
+	_ = os.WriteFile(path, broken, 0o644)
+	if err := loaded.LoadFromFile(path); !errors.Is(err, ErrVersionMismatch) {
+		panic(err)
+	}
+
+	// 不是快照文件
+	_ = os.WriteFile(path, []byte("hello world"), 0o644)
+	if err := loaded.LoadFromFile(path); !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+}
+
+func TestReadSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	cache := New[int, string](10, nil, nil)
+	cache.Put(1, "a")
+	cache.Put(2, "b")
+	if err := cache.SaveToFile(path); err != nil {
+		panic(err)
+	}
+
+	file, _ := os.Open(path)
+	defer file.Close()
+	header, err := ReadSnapshotHeader(file)
+	if err != nil || header.Encrypted || header.Version != formatVersion {
+		panic(header)
+	}
+	_, _ = file.Seek(0, 0)
+	entries, err := ReadSnapshot[int, string](file, nil)
+	if err != nil {
+		panic(err)
+	}
+	if len(entries) != 2 || entries[0].Key != 1 || entries[1].Value != "b" {
+		panic(entries)
+	}
+
+	var buf bytes.Buffer
+	if err = WriteSnapshot(&buf, nil, entries[:1]); err != nil {
+		panic(err)
+	}
+	_ = os.WriteFile(path, buf.Bytes(), 0o644)
+	loaded := New[int, string](10, nil, nil)
+	if err = loaded.LoadFromFile(path); err != nil {
+		panic(err)
+	}
+	if !reflect.DeepEqual(loaded.AllKeys(), []int{1}) {
+		panic(loaded.AllKeys())
+	}
+}
+
+func TestCache_LoadFromFile_Alias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot")
+	saved := New[string, int](10, nil, nil)
+	saved.Put("a", 2)
+	if err := saved.SaveToFile(path); err != nil {
+		panic(err)
+	}
+
+	// 快照中的 key 是已加载缓存的别名时写入 primary
+	cache := New[string, int](10, nil, nil)
+	cache.Put("p", 1)
+	cache.Alias("a", "p")
+	if err := cache.LoadFromFile(path); err != nil {
+		panic(err)
+	}
+	if !reflect.DeepEqual(cache.AllKeys(), []string{"p"}) {
+		panic(cache.AllKeys())
+	}
+	if value, _ := cache.Get("p"); value != 2 {
+		panic(value)
+	}
+}
@@ -0,0 +1,29 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// WithPprofLabels 为加载函数和后台 goroutine 加上 pprof 标签，使 CPU profile 中缓存相关的耗时可以归属到具体的缓存和操作
+// 标签 lru_cache 为 name，lru_op 为操作：load（Do）、load_all（DoAll）、refresh（后台刷新）、
+// compact_wal（预写日志压缩）、persist（WithPersistence 定期保存）、listener（异步监听）和 warm（WarmFromChannel）。
+// DoContext 的加载函数继承 ctx 中已有的标签
+func WithPprofLabels[K comparable, V interface{}](name string) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.pprofLabels = true
+		o.pprofName = name
+	}
+}
+
+// pprofDo 带标签执行 fn，fn 的参数为带标签的 ctx。未开启 WithPprofLabels 时直接以 ctx 执行
+func (c *Cache[K, V]) pprofDo(ctx context.Context, op string, fn func(ctx context.Context)) {
+	name := c.labelName.Load()
+	if name == nil {
+		fn(ctx)
+		return
+	}
+	pprof.Do(ctx, pprof.Labels("lru_cache", *name, "lru_op", op), fn)
+}
@@ -0,0 +1,44 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+)
+
+func TestCache_WithPprofLabels(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithPprofLabels[string, int]("users"))
+	_, err := cache.DoContext(context.Background(), "a", func(ctx context.Context) (int, error) {
+		name, _ := pprof.Label(ctx, "lru_cache")
+		op, _ := pprof.Label(ctx, "lru_op")
+		t.Log(name, op)
+		if name != "users" || op != "load" {
+			panic(name + op)
+		}
+		return 1, nil
+	}, time.Minute)
+	if err != nil {
+		panic(err)
+	}
+
+	plain := New[string, int](10, nil, nil)
+	_, _ = plain.DoContext(context.Background(), "a", func(ctx context.Context) (int, error) {
+		if _, ok := pprof.Label(ctx, "lru_op"); ok {
+			panic("labeled")
+		}
+		return 1, nil
+	}, time.Minute)
+
+	if err := cache.Configure(WithPprofLabels[string, int]("orders")); err != nil {
+		panic(err)
+	}
+	_, _ = cache.DoContext(context.Background(), "b", func(ctx context.Context) (int, error) {
+		if name, _ := pprof.Label(ctx, "lru_cache"); name != "orders" {
+			panic(name)
+		}
+		return 1, nil
+	}, time.Minute)
+}
@@ -0,0 +1,19 @@
+package lru
+
+import "container/list"
+
+// WithPromotionSampling Get 命中时每 n 次才把缓存项移到最前，n <= 1 表示每次命中都移动
+// 非常热的 key 反复 MoveToFront 是纯开销，抽样后访问顺序仍然近似正确：热点项很快会再次被移到前面。
+// 按缓存项的命中次数计数，WithFrequencyDecay 衰减命中次数时抽样的相位随之改变。GetNoMove 不受影响
+func WithPromotionSampling[K comparable, V interface{}](n int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.promoteEvery = n
+	}
+}
+
+// promoteUnlock 第 hits 次命中时按 WithPromotionSampling 决定是否移到最前
+func (c *Cache[K, V]) promoteUnlock(ele *list.Element, hits uint64) {
+	if c.promoteEvery <= 1 || hits%uint64(c.promoteEvery) == 0 {
+		c.li.MoveToFront(ele)
+	}
+}
@@ -0,0 +1,34 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCache_WithPromotionSampling(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithPromotionSampling[string, int](3))
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	for i := 0; i < 2; i++ {
+		if value, ok := cache.Get("a"); !ok || value != 1 {
+			panic(value)
+		}
+		if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+			panic(keys)
+		}
+	}
+	cache.Get("a")
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		panic(keys)
+	}
+	t.Log(cache.AllKeys())
+
+	every := New[string, int](10, nil, nil, WithPromotionSampling[string, int](0))
+	every.Put("a", 1)
+	every.Put("b", 2)
+	every.Get("a")
+	if keys := every.AllKeys(); !reflect.DeepEqual(keys, []string{"a", "b"}) {
+		panic(keys)
+	}
+}
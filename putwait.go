@@ -0,0 +1,90 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTooLarge 缓存项的大小超过 maxSize，无论等待多久都放不下
+var ErrTooLarge = errors.New("lru: entry larger than max size")
+
+// PutWait 同 Put，但空间不足且剩余的项都处于 WithMinResidency 保护期时阻塞等待，而不是超出 maxSize 写入
+// Reserve 预留的空间不可用。有项被移除、淘汰、过期，保护期结束，预留被释放或 Configure 修改容量时重新检查，ctx 结束时返回 ctx.Err()。
+// value 大于 maxSize 时返回 ErrTooLarge，TryPut 会拒绝的写入返回相同的错误。用于缓冲池等不允许超出容量的场景
+func (c *Cache[K, V]) PutWait(ctx context.Context, key K, value V) error {
+	for {
+		c.lock.Lock()
+		if err := c.checkKey(key); err != nil {
+			c.lock.Unlock()
+			return err
+		}
+		resolved := c.resolveUnlock(key)
+		need := c.sizeOf(resolved, value)
+		if need > c.maxSize {
+			c.lock.Unlock()
+			return fmt.Errorf("%w: size %d, max size %d", ErrTooLarge, need, c.maxSize)
+		}
+		wake, ok := c.roomUnlock(resolved, need)
+		if ok {
+			err := c.tryPutUnlock(key, value, 0)
+			c.lock.Unlock()
+			return err
+		}
+		if c.freed == nil {
+			c.freed = make(chan struct{})
+		}
+		freed := c.freed
+		c.lock.Unlock()
+
+		wait := time.Until(wake)
+		if wake.IsZero() { // 只能等待 Reserve 释放或其他项被移除
+			wait = time.Hour
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-freed:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// roomUnlock 淘汰未受保护的项后能否放下 need 大小的 key，不能时返回最早结束保护期的时间
+func (c *Cache[K, V]) roomUnlock(key K, need int) (wake time.Time, ok bool) {
+	free := c.limitUnlock() - c.curSize
+	if ele, found := c.m[key]; found {
+		e := ele.Value.(*Entry[K, V])
+		free += c.sizeOf(e.key, e.value)
+	}
+	if need <= free {
+		return wake, true
+	}
+	now := time.Now()
+	for ele := c.li.Back(); ele != nil; ele = ele.Prev() {
+		e := ele.Value.(*Entry[K, V])
+		if e.key == key {
+			continue
+		}
+		if !c.protected(e, now) {
+			if free += c.sizeOf(e.key, e.value); need <= free {
+				return wake, true
+			}
+		} else if end := e.createTime.Add(c.minResidency); wake.IsZero() || end.Before(wake) {
+			wake = end
+		}
+	}
+	return wake, false
+}
+
+// signalFreedUnlock 缓存变小或容量变化时唤醒 PutWait
+func (c *Cache[K, V]) signalFreedUnlock() {
+	if c.freed != nil {
+		close(c.freed)
+		c.freed = nil
+	}
+}
@@ -0,0 +1,58 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCache_PutWait(t *testing.T) {
+	cache := New[string, int](2, nil, nil, WithMinResidency[string, int](100*time.Millisecond))
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+
+	// 空间不足且都在保护期内，ctx 先结束
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := cache.PutWait(ctx, "c", 3); !errors.Is(err, context.DeadlineExceeded) {
+		panic(err)
+	}
+	if cache.Number() != 2 {
+		panic(cache.AllKeys())
+	}
+
+	// 覆盖已有的 key 不需要额外空间
+	if err := cache.PutWait(context.Background(), "a", 11); err != nil {
+		panic(err)
+	}
+
+	// Remove 唤醒等待
+	done := make(chan error)
+	go func() { done <- cache.PutWait(context.Background(), "c", 3) }()
+	time.Sleep(10 * time.Millisecond)
+	cache.Remove("b")
+	select {
+	case err := <-done:
+		if err != nil {
+			panic(err)
+		}
+	case <-time.After(50 * time.Millisecond):
+		panic("not woken by Remove")
+	}
+
+	// 保护期结束后淘汰最久未访问的项
+	start := time.Now()
+	if err := cache.PutWait(context.Background(), "d", 4); err != nil {
+		panic(err)
+	}
+	t.Log(time.Since(start), cache.AllKeys())
+	if _, ok := cache.GetNoMove("a"); ok || cache.Number() != 2 {
+		panic(cache.AllKeys())
+	}
+
+	sized := New[string, string](2, nil, func(key, value string) int { return len(value) })
+	if err := sized.PutWait(context.Background(), "big", "xyz"); !errors.Is(err, ErrTooLarge) {
+		panic(err)
+	}
+}
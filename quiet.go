@@ -0,0 +1,33 @@
+package lru
+
+import "time"
+
+// UpdateQuietly 替换已存在的 key 的 value 并更新大小，不改变它在访问顺序中的位置，也不更新访问时间和过期时间
+// 用于后台刷新，避免刷新过但没有被访问的项看起来很热。被 Invalidate 标记的失效状态被清除。
+// key 不存在、已过期或 SizeStrict 下大小不合法时返回 false 且不写入。更新照常写入预写日志并通知 EventPut 监听
+func (c *Cache[K, V]) UpdateQuietly(key K, value V) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key = c.resolveUnlock(key)
+	ele, ok := c.m[key]
+	if !ok || c.checkSize(key, value) != nil {
+		return false
+	}
+	e := ele.Value.(*Entry[K, V])
+	if e.expired(time.Now()) {
+		return false
+	}
+	c.churnRollUnlock()
+	c.churn.puts++
+	c.churn.replaces++
+	c.accountUnlock(key, -c.sizeOf(key, e.value))
+	e.value = value
+	e.stale = false
+	delete(c.valueTypes, key)
+	c.accountUnlock(key, c.sizeOf(key, value))
+	c.logUnlock(opPut, key, value, e.expireAt)
+	c.notifyUnlock(EventPut, key, value)
+	c.enforceQuotaUnlock(key)
+	c.expireUnlock()
+	return true
+}
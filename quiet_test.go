@@ -0,0 +1,35 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_UpdateQuietly(t *testing.T) {
+	cache := New[string, string](6, nil, func(key, value string) int { return len(value) })
+	cache.Put("a", "1")
+	cache.PutWithTTL("b", "2", time.Hour)
+	cache.Put("c", "3")
+
+	if !cache.UpdateQuietly("b", "22") {
+		panic("b")
+	}
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"c", "b", "a"}) {
+		panic(keys)
+	}
+	value, _, expireAt, _ := cache.GetWithExpiration("b")
+	if value != "22" || expireAt.IsZero() || cache.Size() != 4 {
+		panic(value)
+	}
+	if cache.UpdateQuietly("missing", "x") {
+		panic("missing")
+	}
+
+	// 变大后按 LRU 淘汰，位置不变的 a 最先被淘汰
+	cache.UpdateQuietly("c", "3333")
+	t.Log(cache.AllKeys())
+	if _, ok := cache.GetNoMove("a"); ok || cache.Size() != 6 {
+		panic(cache.AllKeys())
+	}
+}
@@ -0,0 +1,60 @@
+package lru
+
+import "time"
+
+// WithQuota 按标签限制配额，每个标签的缓存项大小之和不超过 maxShare * maxSize
+// label 将 key 归类，例如按租户或数据类别。某个标签超出配额时，淘汰该标签下最近最少使用的项，
+// 而不是整个缓存中最近最少使用的项，避免单个租户挤占其他租户。maxShare 取值 (0, 1]
+func WithQuota[K comparable, V interface{}](label func(key K) string, maxShare float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.quotaLabel = label
+		o.quotaShare = maxShare
+	}
+}
+
+// QuotaUsage 返回 WithQuota 下每个标签占用的缓存大小
+func (c *Cache[K, V]) QuotaUsage() map[string]int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	usage := make(map[string]int, len(c.quotaSizes))
+	for label, size := range c.quotaSizes {
+		usage[label] = size
+	}
+	return usage
+}
+
+func (c *Cache[K, V]) accountQuotaUnlock(key K, delta int) {
+	if c.quotaSizes == nil {
+		c.quotaSizes = map[string]int{}
+	}
+	label := c.quotaLabel(key)
+	size := c.quotaSizes[label] + delta
+	if size == 0 {
+		delete(c.quotaSizes, label)
+	} else {
+		c.quotaSizes[label] = size
+	}
+}
+
+// enforceQuotaUnlock key 写入后，若其标签超出配额则从后往前淘汰该标签下的项
+func (c *Cache[K, V]) enforceQuotaUnlock(key K) {
+	if c.quotaLabel == nil || c.replaying {
+		return
+	}
+	c.enforceLabelUnlock(c.quotaLabel(key))
+}
+
+// enforceLabelUnlock 淘汰 label 下的项直到不超过配额
+func (c *Cache[K, V]) enforceLabelUnlock(label string) {
+	limit := int(c.quotaShare * float64(c.maxSize))
+	now := time.Now()
+	ele := c.li.Back()
+	for c.quotaSizes[label] > limit && ele != nil {
+		prev := ele.Prev()
+		if e := ele.Value.(*Entry[K, V]); c.quotaLabel(e.key) == label && !c.protected(e, now) {
+			c.evictUnlock(ele)
+			prev = c.li.Back() // 淘汰可能级联移除 prev，从尾部重新开始
+		}
+		ele = prev
+	}
+}
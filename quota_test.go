@@ -0,0 +1,36 @@
+package lru
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithQuota(t *testing.T) {
+	tenant := func(key string) string { return strings.Split(key, "/")[0] }
+	cache := New[string, int](10, nil, nil, WithQuota[string, int](tenant, 0.5))
+	cache.Put("a/1", 1)
+	cache.Put("a/2", 2)
+	for i := 0; i < 10; i++ {
+		cache.Put("noisy/"+string(rune('0'+i)), i)
+	}
+
+	usage := cache.QuotaUsage()
+	if !reflect.DeepEqual(usage, map[string]int{"a": 2, "noisy": 5}) {
+		panic(usage)
+	}
+	if _, ok := cache.Get("a/1"); !ok {
+		panic("a/1 evicted by noisy neighbor")
+	}
+	if _, ok := cache.Get("noisy/4"); ok {
+		panic("noisy/4 should be evicted")
+	}
+	if stats := cache.Stats(); stats.Evictions != 5 {
+		panic(stats.Evictions)
+	}
+
+	cache.RemoveAll()
+	if len(cache.QuotaUsage()) != 0 {
+		panic(cache.QuotaUsage())
+	}
+}
@@ -0,0 +1,86 @@
+package lru
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithExpireRateLimit 限制失效回调每秒最多执行 perSecond 次，防止扩容缩容或大批量淘汰时压垮下游
+// 超出限制的失效项不执行失效回调：batch 不为空时攒起来，每秒最多一次批量交给 batch 在后台 goroutine 中处理，
+// 每批最多 10000 项，攒满后的失效项丢弃，避免持续的大批量淘汰使内存无限增长；
+// batch 为空时直接丢弃。被丢弃的数目见 Stats().ExpireDropped
+func WithExpireRateLimit[K comparable, V interface{}](perSecond int, batch func(keys []K, values []V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.expirePerSecond = perSecond
+		o.expireBatch = batch
+	}
+}
+
+// maxExpireBatch 每批最多攒下的失效项数目
+const maxExpireBatch = 10000
+
+// expireLimiter 令牌桶限速的失效回调
+type expireLimiter[K comparable, V interface{}] struct {
+	callback  func(key K, value V)
+	batch     func(keys []K, values []V)
+	perSecond float64
+	dropped   atomic.Uint64
+
+	lock      sync.Mutex
+	tokens    float64
+	last      time.Time
+	keys      []K
+	values    []V
+	scheduled bool // 是否已安排批量处理
+}
+
+func newExpireLimiter[K comparable, V interface{}](perSecond int, callback func(key K, value V), batch func(keys []K, values []V)) *expireLimiter[K, V] {
+	return &expireLimiter[K, V]{
+		callback:  callback,
+		batch:     batch,
+		perSecond: float64(perSecond),
+		tokens:    float64(perSecond),
+		last:      time.Now(),
+	}
+}
+
+func (l *expireLimiter[K, V]) expire(key K, value V) {
+	l.lock.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.perSecond
+	if l.tokens > l.perSecond {
+		l.tokens = l.perSecond
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		l.lock.Unlock()
+		l.callback(key, value)
+		return
+	}
+
+	if l.batch == nil || len(l.keys) >= maxExpireBatch {
+		l.lock.Unlock()
+		l.dropped.Add(1)
+		return
+	}
+	l.keys = append(l.keys, key)
+	l.values = append(l.values, value)
+	if !l.scheduled {
+		l.scheduled = true
+		time.AfterFunc(time.Second, l.flush)
+	}
+	l.lock.Unlock()
+}
+
+func (l *expireLimiter[K, V]) flush() {
+	l.lock.Lock()
+	keys, values := l.keys, l.values
+	l.keys, l.values = nil, nil
+	l.scheduled = false
+	l.lock.Unlock()
+	if len(keys) > 0 {
+		l.batch(keys, values)
+	}
+}
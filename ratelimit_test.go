@@ -0,0 +1,74 @@
+package lru
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithExpireRateLimit(t *testing.T) {
+	expired := 0
+	cache := New[int, int](1, func(key int, value int) { expired++ }, nil,
+		WithExpireRateLimit[int, int](5, nil))
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+	}
+	if expired != 5 {
+		panic(expired)
+	}
+	if dropped := cache.Stats().ExpireDropped; dropped != 14 {
+		panic(dropped)
+	}
+}
+
+func TestWithExpireRateLimit_Batch(t *testing.T) {
+	expired := 0
+	var lock sync.Mutex
+	var batched []int
+	done := make(chan struct{})
+	cache := New[int, int](1, func(key int, value int) { expired++ }, nil,
+		WithExpireRateLimit[int, int](5, func(keys []int, values []int) {
+			lock.Lock()
+			batched = append(batched, keys...)
+			lock.Unlock()
+			close(done)
+		}))
+	for i := 0; i < 20; i++ {
+		cache.Put(i, i)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		panic("batch not flushed")
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if expired != 5 || len(batched) != 14 || batched[0] != 5 {
+		panic(batched)
+	}
+	if dropped := cache.Stats().ExpireDropped; dropped != 0 {
+		panic(dropped)
+	}
+}
+
+func TestWithExpireRateLimit_BatchFull(t *testing.T) {
+	var lock sync.Mutex
+	batched := 0
+	cache := New[int, int](1, nil, nil, WithExpireRateLimit[int, int](1, func(keys []int, values []int) {
+		lock.Lock()
+		batched += len(keys)
+		lock.Unlock()
+	}))
+	n := maxExpireBatch + 100
+	for i := 0; i < n; i++ {
+		cache.Put(i, i)
+	}
+	// 1 个执行回调，maxExpireBatch 个等待批量处理，其余丢弃
+	if dropped := cache.Stats().ExpireDropped; dropped != uint64(n-2-maxExpireBatch) {
+		panic(dropped)
+	}
+	if backlog := cache.Health().ExpireBacklog; backlog != maxExpireBatch {
+		panic(backlog)
+	}
+}
@@ -0,0 +1,183 @@
+// Package redissync 在 Redis 与进程内缓存之间同步数据，用于从远程 Redis 迁移到进程内缓存的过渡期
+// Load 通过 SCAN + MGET 将 Redis 中的数据批量载入缓存，Mirror 将缓存的写入与失效异步回写到 Redis。
+// 两者都支持限速，避免冲击线上 Redis
+package redissync
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/madokast/LRU"
+)
+
+// limiter 限制每秒执行的命令数，perSecond <= 0 表示不限速
+type limiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newLimiter(perSecond int) limiter {
+	if perSecond <= 0 {
+		return limiter{}
+	}
+	return limiter{interval: time.Second / time.Duration(perSecond)}
+}
+
+func (l *limiter) wait() {
+	if l.interval == 0 {
+		return
+	}
+	now := time.Now()
+	if l.next.After(now) {
+		time.Sleep(l.next.Sub(now))
+		now = l.next
+	}
+	l.next = now.Add(l.interval)
+}
+
+// Load 将 Redis 中匹配 pattern 的 key 载入缓存，返回载入的数目
+// batch 为每次 SCAN 的 COUNT 提示，perSecond 限制每秒发出的命令数（<= 0 不限速）。
+// 扫描期间被删除的 key 会被跳过，value 非字符串类型的 key 会导致 MGET 返回 nil 从而被跳过
+func Load(client *Client, cache lru.Cacher[string, []byte], pattern string, batch int, perSecond int) (int, error) {
+	if batch <= 0 {
+		batch = 100
+	}
+	l := newLimiter(perSecond)
+	loaded := 0
+	cursor := "0"
+	for {
+		l.wait()
+		reply, err := client.Do("SCAN", cursor, "MATCH", pattern, "COUNT", strconv.Itoa(batch))
+		if err != nil {
+			return loaded, err
+		}
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return loaded, errors.New("redissync: unexpected SCAN reply")
+		}
+		next, ok1 := parts[0].([]byte)
+		keys, ok2 := parts[1].([]interface{})
+		if !ok1 || !ok2 {
+			return loaded, errors.New("redissync: unexpected SCAN reply")
+		}
+
+		if len(keys) > 0 {
+			args := make([]string, 0, len(keys)+1)
+			args = append(args, "MGET")
+			for _, key := range keys {
+				k, _ := key.([]byte)
+				args = append(args, string(k))
+			}
+			l.wait()
+			reply, err = client.Do(args...)
+			if err != nil {
+				return loaded, err
+			}
+			values, ok := reply.([]interface{})
+			if !ok || len(values) != len(keys) {
+				return loaded, errors.New("redissync: unexpected MGET reply")
+			}
+			for i, value := range values {
+				if v, ok := value.([]byte); ok && v != nil {
+					cache.Put(args[i+1], v)
+					loaded++
+				}
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" {
+			return loaded, nil
+		}
+	}
+}
+
+// mirrorOp 待回写的操作，value 为 nil 表示删除
+type mirrorOp struct {
+	key   string
+	value []byte
+}
+
+// Mirror 将缓存的写入和失效异步回写到 Redis
+// 回写在后台 goroutine 中按限速依次执行，队列满时丢弃新的操作并计数，不会阻塞调用方。
+// Expire 可以直接作为 lru.New 的失效回调
+type Mirror struct {
+	client  *Client
+	ops     chan mirrorOp
+	limiter limiter
+	dropped atomic.Uint64
+	done    chan struct{}
+
+	lock sync.Mutex
+	err  error // 第一个回写错误
+}
+
+// NewMirror 创建回写器，queueSize 为队列长度，perSecond 限制每秒回写的命令数（<= 0 不限速）
+func NewMirror(client *Client, queueSize int, perSecond int) *Mirror {
+	m := &Mirror{
+		client:  client,
+		ops:     make(chan mirrorOp, queueSize),
+		limiter: newLimiter(perSecond),
+		done:    make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+func (m *Mirror) run() {
+	defer close(m.done)
+	for op := range m.ops {
+		m.limiter.wait()
+		var err error
+		if op.value == nil {
+			_, err = m.client.Do("DEL", op.key)
+		} else {
+			_, err = m.client.Do("SET", op.key, string(op.value))
+		}
+		if err != nil {
+			m.lock.Lock()
+			if m.err == nil {
+				m.err = err
+			}
+			m.lock.Unlock()
+		}
+	}
+}
+
+func (m *Mirror) enqueue(op mirrorOp) {
+	select {
+	case m.ops <- op:
+	default:
+		m.dropped.Add(1)
+	}
+}
+
+// Put 回写 SET key value
+func (m *Mirror) Put(key string, value []byte) {
+	if value == nil {
+		value = []byte{}
+	}
+	m.enqueue(mirrorOp{key: key, value: value})
+}
+
+// Expire 回写 DEL key，签名与 lru.New 的失效回调一致
+func (m *Mirror) Expire(key string, _ []byte) {
+	m.enqueue(mirrorOp{key: key})
+}
+
+// Dropped 返回因队列满被丢弃的操作数
+func (m *Mirror) Dropped() uint64 {
+	return m.dropped.Load()
+}
+
+// Close 等待队列中的操作回写完毕，返回第一个回写错误。Close 之后不能再调用 Put/Expire
+func (m *Mirror) Close() error {
+	close(m.ops)
+	<-m.done
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.err
+}
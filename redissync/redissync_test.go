@@ -0,0 +1,204 @@
+package redissync
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	lru "github.com/madokast/LRU"
+)
+
+// fakeRedis 只实现测试用到的 SCAN/MGET/SET/DEL，SCAN 每页返回一个 key
+// GARBAGE 和 HUGE 返回不合法的回复，用于测试协议错误
+type fakeRedis struct {
+	lock  sync.Mutex
+	data  map[string]string
+	conns int // 接受的连接数
+}
+
+func (f *fakeRedis) serve(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			f.lock.Lock()
+			f.conns++
+			f.lock.Unlock()
+			go f.handle(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		var args []string
+		for _, arg := range reply.([]interface{}) {
+			args = append(args, string(arg.([]byte)))
+		}
+
+		f.lock.Lock()
+		switch args[0] {
+		case "SCAN":
+			var keys []string
+			prefix := strings.TrimSuffix(args[3], "*")
+			for k := range f.data {
+				if strings.HasPrefix(k, prefix) {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			cursor, _ := strconv.Atoi(args[1])
+			next := cursor + 1
+			if next >= len(keys) {
+				next = 0
+			}
+			fmt.Fprintf(w, "*2\r\n$%d\r\n%d\r\n", len(strconv.Itoa(next)), next)
+			if cursor < len(keys) {
+				fmt.Fprintf(w, "*1\r\n$%d\r\n%s\r\n", len(keys[cursor]), keys[cursor])
+			} else {
+				fmt.Fprintf(w, "*0\r\n")
+			}
+		case "MGET":
+			fmt.Fprintf(w, "*%d\r\n", len(args)-1)
+			for _, k := range args[1:] {
+				if v, ok := f.data[k]; ok {
+					fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+				} else {
+					fmt.Fprintf(w, "$-1\r\n")
+				}
+			}
+		case "SET":
+			f.data[args[1]] = args[2]
+			fmt.Fprintf(w, "+OK\r\n")
+		case "DEL":
+			delete(f.data, args[1])
+			fmt.Fprintf(w, ":1\r\n")
+		case "GARBAGE": // 长度不是数字，后面残留的内容会被当作下一条命令的回复
+			fmt.Fprintf(w, "$x\r\n+STALE\r\n")
+		case "HUGE":
+			fmt.Fprintf(w, "$99999999999\r\n")
+		default:
+			fmt.Fprintf(w, "-ERR unknown command\r\n")
+		}
+		f.lock.Unlock()
+		_ = w.Flush()
+	}
+}
+
+func TestLoad(t *testing.T) {
+	f := &fakeRedis{data: map[string]string{"user:1": "a", "user:2": "b", "user:3": "c", "other": "d"}}
+	client, err := Dial(f.serve(t), time.Second)
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	cache := lru.New[string, []byte](10, nil, nil)
+	n, err := Load(client, cache, "user:*", 10, 0)
+	if err != nil {
+		panic(err)
+	}
+	if n != 3 || cache.Number() != 3 {
+		panic(n)
+	}
+	if value, _ := cache.Get("user:2"); string(value) != "b" {
+		panic(string(value))
+	}
+
+	if _, err = client.Do("FLUSHALL"); err == nil {
+		panic("expect error reply")
+	}
+}
+
+func TestMirror(t *testing.T) {
+	f := &fakeRedis{data: map[string]string{}}
+	client, err := Dial(f.serve(t), time.Second)
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	mirror := NewMirror(client, 100, 1000)
+	cache := lru.New[string, []byte](2, mirror.Expire, nil)
+	for i := 0; i < 3; i++ {
+		key := strconv.Itoa(i)
+		cache.Put(key, []byte(key))
+		mirror.Put(key, []byte(key))
+	}
+	if err = mirror.Close(); err != nil {
+		panic(err)
+	}
+	if mirror.Dropped() != 0 {
+		panic(mirror.Dropped())
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if len(f.data) != 2 || f.data["2"] != "2" {
+		panic(f.data)
+	}
+}
+
+func TestClient_ProtocolError(t *testing.T) {
+	f := &fakeRedis{data: map[string]string{}}
+	client, err := Dial(f.serve(t), time.Second)
+	if err != nil {
+		panic(err)
+	}
+	defer client.Close()
+
+	for _, cmd := range []string{"GARBAGE", "HUGE"} {
+		if _, err := client.Do(cmd); err == nil {
+			panic(cmd)
+		}
+		// 出错后重新连接，不会读到旧连接上残留的回复
+		if reply, err := client.Do("SET", cmd, "v"); err != nil || reply != "OK" {
+			panic(reply)
+		}
+	}
+	f.lock.Lock()
+	conns := f.conns
+	f.lock.Unlock()
+	if conns != 3 {
+		panic(conns)
+	}
+
+	client.Close()
+	if _, err := client.Do("SET", "k", "v"); err != ErrClosed {
+		panic(err)
+	}
+
+	// NewClient 不能重连
+	conn, err := net.Dial("tcp", f.serve(t))
+	if err != nil {
+		panic(err)
+	}
+	client = NewClient(conn)
+	if _, err := client.Do("GARBAGE"); err == nil {
+		panic("GARBAGE")
+	}
+	if _, err := client.Do("SET", "k", "v"); err != ErrClosed {
+		panic(err)
+	}
+}
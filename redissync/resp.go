@@ -0,0 +1,211 @@
+package redissync
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Error Redis 返回的错误回复
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// 单个回复的上限，与 Redis 的 proto-max-bulk-len 默认值一致，超出视为协议错误，避免按损坏的长度分配内存
+const (
+	maxBulkLen  = 512 << 20
+	maxArrayLen = 1 << 24
+)
+
+// ErrClosed Close 之后调用 Do，或 NewClient 创建的客户端连接已因错误关闭
+var ErrClosed = errors.New("redissync: client closed")
+
+// Client 最小的 Redis 客户端，只支持 RESP2 请求-回复，并发安全
+// 读写或解析回复出错时连接的读写位置已不可信，关闭该连接；Dial 创建的客户端在下一次 Do 时重新连接
+type Client struct {
+	lock sync.Mutex // 串行执行命令，保护 r、w
+	r    *bufio.Reader
+	w    *bufio.Writer
+
+	connLock sync.Mutex // 保护 conn、closed，Close 不必等待进行中的命令
+	conn     net.Conn   // 为空表示已因错误关闭
+	closed   bool
+	dial     func() (net.Conn, error) // 为空表示不能重连
+}
+
+// Dial 连接 Redis，addr 形如 "127.0.0.1:6379"
+func Dial(addr string, timeout time.Duration) (*Client, error) {
+	dial := func() (net.Conn, error) { return net.DialTimeout("tcp", addr, timeout) }
+	conn, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	c := NewClient(conn)
+	c.dial = dial
+	return c, nil
+}
+
+// NewClient 在已有连接上创建客户端，连接出错关闭后不会重连
+func NewClient(conn net.Conn) *Client {
+	return &Client{conn: conn, r: bufio.NewReader(conn), w: bufio.NewWriter(conn)}
+}
+
+// Do 执行一条命令
+// 回复按类型转为 string（简单字符串）、int64（整数）、[]byte（批量字符串，nil 表示不存在）、[]interface{}（数组），错误回复返回 Error
+func (c *Client) Do(args ...string) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	if err = writeCommand(c.w, args); err == nil {
+		err = c.w.Flush()
+	}
+	var reply interface{}
+	if err == nil {
+		reply, err = readReply(c.r)
+	}
+	if err != nil {
+		c.drop(conn)
+		return nil, err
+	}
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// connect 返回当前连接，已因错误关闭时重新连接
+func (c *Client) connect() (net.Conn, error) {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.conn != nil {
+		return c.conn, nil
+	}
+	if c.dial == nil {
+		return nil, ErrClosed
+	}
+	conn, err := c.dial()
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+	c.r.Reset(conn)
+	c.w.Reset(conn)
+	return conn, nil
+}
+
+// drop 关闭出错的连接
+func (c *Client) drop(conn net.Conn) {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	if c.conn == conn {
+		c.conn = nil
+	}
+	_ = conn.Close()
+}
+
+// Close 关闭连接，之后 Do 返回 ErrClosed
+func (c *Client) Close() error {
+	c.connLock.Lock()
+	defer c.connLock.Unlock()
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+	conn := c.conn
+	c.conn = nil
+	return conn.Close()
+}
+
+func writeCommand(w *bufio.Writer, args []string) error {
+	if _, err := fmt.Fprintf(w, "*%d\r\n", len(args)); err != nil {
+		return err
+	}
+	for _, arg := range args {
+		if _, err := fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errors.New("redissync: malformed reply line")
+	}
+	return line[:len(line)-2], nil
+}
+
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redissync: empty reply line")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return []byte(nil), nil
+		}
+		if n > maxBulkLen {
+			return nil, fmt.Errorf("redissync: bulk string length %d exceeds %d", n, maxBulkLen)
+		}
+		buf := make([]byte, n+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		if buf[n] != '\r' || buf[n+1] != '\n' {
+			return nil, errors.New("redissync: malformed bulk string")
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return []interface{}(nil), nil
+		}
+		if n > maxArrayLen {
+			return nil, fmt.Errorf("redissync: array length %d exceeds %d", n, maxArrayLen)
+		}
+		replies := make([]interface{}, n)
+		for i := range replies {
+			if replies[i], err = readReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	default:
+		return nil, fmt.Errorf("redissync: unknown reply type %q", line[0])
+	}
+}
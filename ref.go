@@ -0,0 +1,55 @@
+package lru
+
+import "sync"
+
+// Ref GetRef 返回的引用，持有期间缓存项的失效函数不会执行
+// 失效函数通常用于释放或回收 value（例如放回对象池），引用保证 value 在 Release 之前不会被回收
+type Ref[V interface{}] struct {
+	value   V
+	once    sync.Once
+	release func()
+}
+
+// Value 返回 GetRef 时的 value，之后缓存中的更新不影响引用
+func (r *Ref[V]) Value() V {
+	return r.value
+}
+
+// Release 释放引用，重复调用无副作用。缓存项已被移除且这是最后一个引用时，在此执行推迟的失效函数
+func (r *Ref[V]) Release() {
+	r.once.Do(r.release)
+}
+
+// GetRef 同 Get，命中时返回引用计数的句柄
+// 缓存项在引用全部释放之前被淘汰、过期或移除时，失效函数推迟到最后一个 Release 执行，其余行为（统计、监听、淘汰顺序）不变。
+// 忘记 Release 会使失效函数永远不执行
+func (c *Cache[K, V]) GetRef(key K) (*Ref[V], bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	value, ok := c.getUnlock(key)
+	if !ok {
+		return nil, false
+	}
+	e := c.m[c.resolveUnlock(key)].Value.(*Entry[K, V])
+	e.refs++
+	return &Ref[V]{value: value, release: func() { c.releaseRef(e) }}, true
+}
+
+func (c *Cache[K, V]) releaseRef(e *Entry[K, V]) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e.refs--
+	if e.refs == 0 && e.deferred {
+		e.deferred = false
+		c.expireCallback(e.key, e.value)
+	}
+}
+
+// expireEntryUnlock 执行缓存项的失效函数，仍有引用时推迟到引用全部释放
+func (c *Cache[K, V]) expireEntryUnlock(e *Entry[K, V]) {
+	if e.refs > 0 {
+		e.deferred = true
+		return
+	}
+	c.expireCallback(e.key, e.value)
+}
@@ -0,0 +1,43 @@
+package lru
+
+import (
+	"testing"
+)
+
+func TestCache_GetRef(t *testing.T) {
+	var expired []string
+	cache := New[string, int](1, func(key string, value int) { expired = append(expired, key) }, nil)
+	cache.Put("a", 1)
+
+	ref, ok := cache.GetRef("a")
+	if !ok || ref.Value() != 1 {
+		panic(ok)
+	}
+	ref2, _ := cache.GetRef("a")
+	if _, ok := cache.GetRef("x"); ok {
+		panic("x")
+	}
+
+	cache.Put("b", 2) // 淘汰 a，失效函数推迟
+	if _, ok := cache.GetNoMove("a"); ok || len(expired) != 0 {
+		panic(expired)
+	}
+	ref.Release()
+	ref.Release()
+	if len(expired) != 0 {
+		panic(expired)
+	}
+	ref2.Release()
+	if len(expired) != 1 || expired[0] != "a" {
+		panic(expired)
+	}
+
+	// 没有被移除的项释放引用时不执行失效函数
+	ref3, _ := cache.GetRef("b")
+	ref3.Release()
+	cache.Remove("b")
+	t.Log(expired)
+	if len(expired) != 2 {
+		panic(expired)
+	}
+}
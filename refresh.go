@@ -0,0 +1,129 @@
+package lru
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WithRefreshAhead Do 命中剩余有效期不足 window 的项时，在后台调用 fn 提前刷新，调用方直接得到旧值
+// 热点 key 因此不会在过期的瞬间让所有请求同时阻塞在加载上。同一 key 同时只有一个后台刷新，刷新失败时保留旧值直到过期
+func WithRefreshAhead[K comparable, V interface{}](window time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.refreshAhead = window
+	}
+}
+
+// WithMaxConcurrentRefreshes 限制同时进行的后台刷新数目，<= 0 表示不限
+// 大量热点 key 同时临近过期时，超出上限的 key 不提前刷新，过期后由 Do 同步加载
+func WithMaxConcurrentRefreshes[K comparable, V interface{}](n int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.maxRefreshes = n
+	}
+}
+
+const refreshStripes = 16
+
+// refreshRegistry 按 key 分段加锁登记正在进行的后台刷新，零值可用
+type refreshRegistry[K comparable] struct {
+	seedOnce sync.Once
+	seed     maphash.Seed
+	stripes  [refreshStripes]refreshStripe[K]
+	running  atomic.Int64
+}
+
+type refreshStripe[K comparable] struct {
+	lock sync.Mutex
+	keys map[K]struct{}
+}
+
+func (r *refreshRegistry[K]) stripe(key K) *refreshStripe[K] {
+	var h uint64
+	switch k := interface{}(key).(type) {
+	case string:
+		h = r.hashString(k)
+	case int:
+		h = uint64(k)
+	case int64:
+		h = uint64(k)
+	case uint64:
+		h = k
+	case int32:
+		h = uint64(k)
+	case uint32:
+		h = uint64(k)
+	default:
+		h = r.hashString(fmt.Sprint(k))
+	}
+	return &r.stripes[h%refreshStripes]
+}
+
+func (r *refreshRegistry[K]) hashString(s string) uint64 {
+	r.seedOnce.Do(func() { r.seed = maphash.MakeSeed() })
+	return maphash.String(r.seed, s)
+}
+
+// acquire 登记 key 的刷新，key 已在刷新或超出并发上限 max 时返回 false
+func (r *refreshRegistry[K]) acquire(key K, max int) bool {
+	s := r.stripe(key)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.keys[key]; ok {
+		return false
+	}
+	if n := r.running.Add(1); max > 0 && n > int64(max) {
+		r.running.Add(-1)
+		return false
+	}
+	if s.keys == nil {
+		s.keys = map[K]struct{}{}
+	}
+	s.keys[key] = struct{}{}
+	return true
+}
+
+func (r *refreshRegistry[K]) release(key K) {
+	s := r.stripe(key)
+	s.lock.Lock()
+	delete(s.keys, key)
+	s.lock.Unlock()
+	r.running.Add(-1)
+}
+
+// needRefresh 判断 key 是否已软过期或临近过期需要提前刷新，返回并发上限
+func (c *Cache[K, V]) needRefresh(key K) (refresh bool, max int) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	ele, ok := c.m[c.resolveUnlock(key)]
+	if !ok {
+		return false, 0
+	}
+	e := ele.Value.(*Entry[K, V])
+	now := time.Now()
+	if !e.softAt.IsZero() && !now.Before(e.softAt) {
+		return true, c.maxRefreshes
+	}
+	return c.refreshAhead > 0 && !e.expireAt.IsZero() && e.expireAt.Sub(now) < c.refreshAhead, c.maxRefreshes
+}
+
+// refresh 在后台调用 fn 刷新 key，成功时以 ttl 和 opts 写入
+func (c *Cache[K, V]) refresh(key K, fn func() (V, error), ttl time.Duration, opts []PutOption) {
+	refresh, max := c.needRefresh(key)
+	if !refresh || !c.refreshes.acquire(key, max) {
+		return
+	}
+	go c.pprofDo(context.Background(), "refresh", func(context.Context) {
+		defer c.refreshes.release(key)
+		if value, err := fn(); err == nil {
+			c.PutWithTTL(key, value, ttl, opts...)
+		}
+	})
+}
+
+// Refreshing 返回正在进行的后台刷新数目
+func (c *Cache[K, V]) Refreshing() int {
+	return int(c.refreshes.running.Load())
+}
@@ -0,0 +1,80 @@
+package lru
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_RefreshAhead(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithRefreshAhead[string, int](time.Hour), WithMaxConcurrentRefreshes[string, int](1))
+	cache.PutWithTTL("a", 1, time.Minute)
+	cache.PutWithTTL("b", 1, time.Minute)
+	cache.Put("c", 1) // 永不过期，不刷新
+
+	release := make(chan struct{})
+	var loads atomic.Int32
+	fn := func() (int, error) {
+		loads.Add(1)
+		<-release
+		return 2, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if value, err := cache.Do("a", fn, time.Minute); value != 1 || err != nil {
+			panic(value)
+		}
+	}
+	if value, _ := cache.Do("b", fn, time.Minute); value != 1 {
+		panic(value)
+	}
+	cache.Do("c", fn, time.Minute)
+	if cache.Refreshing() != 1 {
+		panic(cache.Refreshing()) // a 只刷新一次，b 超出上限
+	}
+
+	close(release)
+	for cache.Refreshing() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if value, _ := cache.Get("a"); value != 2 {
+		panic(value)
+	}
+	if value, _ := cache.Get("b"); value != 1 {
+		panic(value)
+	}
+	if loads.Load() != 1 {
+		panic(loads.Load())
+	}
+}
+
+func TestRefreshRegistry(t *testing.T) {
+	var r refreshRegistry[[2]int]
+	if !r.acquire([2]int{1, 2}, 0) || r.acquire([2]int{1, 2}, 0) {
+		panic("duplicate acquire")
+	}
+	if !r.acquire([2]int{3, 4}, 0) {
+		panic("acquire failed")
+	}
+	r.release([2]int{1, 2})
+	if r.running.Load() != 1 {
+		panic(r.running.Load())
+	}
+}
+
+func TestCache_RefreshAhead_Alias(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithRefreshAhead[string, int](time.Hour))
+	cache.PutWithTTL("p", 1, time.Minute)
+	cache.Alias("a", "p")
+	if value, _ := cache.Do("a", func() (int, error) { return 2, nil }, time.Minute); value != 1 {
+		panic(value)
+	}
+	for deadline := time.Now().Add(time.Second); cache.Refreshing() != 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			panic(cache.AllKeys())
+		}
+	}
+	if value, _ := cache.Get("p"); value != 2 {
+		panic(value) // 刷新写入 primary
+	}
+}
@@ -0,0 +1,99 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrDuplicateName Registry 中已有同名的缓存
+var ErrDuplicateName = errors.New("lru: duplicate cache name")
+
+// Monitored 注册到 Registry 的缓存需要提供的方法，任意类型参数的 *Cache 都实现了该接口
+// 采集器与业务读写并发调用这些方法，实现必须自行加锁，*Cache 的实现都在读锁下读取
+type Monitored interface {
+	Stats() Stats
+	Health() HealthReport
+	Size() int
+	Number() int
+	MaxSize() int
+}
+
+var _ Monitored = (*Cache[int, int])(nil)
+
+// NamedCache 注册的缓存及其名字
+type NamedCache struct {
+	Name  string
+	Cache Monitored
+}
+
+// Registry 按名字登记进程内的缓存，供管理接口、expvar 和 Prometheus 采集器统一枚举，无需为每个缓存单独接线
+type Registry struct {
+	lock   sync.RWMutex
+	caches map[string]Monitored
+}
+
+// DefaultRegistry 包级函数 Register、Unregister、Lookup 和 Caches 使用的注册表
+var DefaultRegistry = &Registry{}
+
+// Register 以 name 登记缓存，name 已被使用时返回 ErrDuplicateName
+func (r *Registry) Register(name string, cache Monitored) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.caches[name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateName, name)
+	}
+	if r.caches == nil {
+		r.caches = map[string]Monitored{}
+	}
+	r.caches[name] = cache
+	return nil
+}
+
+// Unregister 移除 name 的登记，name 不存在时无操作
+func (r *Registry) Unregister(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.caches, name)
+}
+
+// Lookup 返回 name 登记的缓存
+func (r *Registry) Lookup(name string) (Monitored, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	cache, ok := r.caches[name]
+	return cache, ok
+}
+
+// Caches 返回全部登记的缓存，按名字排序
+func (r *Registry) Caches() []NamedCache {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	caches := make([]NamedCache, 0, len(r.caches))
+	for name, cache := range r.caches {
+		caches = append(caches, NamedCache{Name: name, Cache: cache})
+	}
+	sort.Slice(caches, func(i, j int) bool { return caches[i].Name < caches[j].Name })
+	return caches
+}
+
+// Register 在 DefaultRegistry 中登记缓存
+func Register(name string, cache Monitored) error {
+	return DefaultRegistry.Register(name, cache)
+}
+
+// Unregister 移除 DefaultRegistry 中的登记
+func Unregister(name string) {
+	DefaultRegistry.Unregister(name)
+}
+
+// Lookup 返回 DefaultRegistry 中 name 登记的缓存
+func Lookup(name string) (Monitored, bool) {
+	return DefaultRegistry.Lookup(name)
+}
+
+// Caches 返回 DefaultRegistry 中全部登记的缓存，按名字排序
+func Caches() []NamedCache {
+	return DefaultRegistry.Caches()
+}
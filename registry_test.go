@@ -0,0 +1,72 @@
+package lru
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	users := New[string, int](10, nil, nil)
+	orders := New[int, string](10, nil, nil)
+	if err := Register("users", users); err != nil {
+		panic(err)
+	}
+	defer Unregister("users")
+	if err := Register("orders", orders); err != nil {
+		panic(err)
+	}
+	defer Unregister("orders")
+	if err := Register("users", orders); !errors.Is(err, ErrDuplicateName) {
+		panic(err)
+	}
+
+	users.Put("a", 1)
+	caches := Caches()
+	t.Log(caches)
+	if len(caches) != 2 || caches[0].Name != "orders" || caches[1].Name != "users" || caches[1].Cache.Number() != 1 {
+		panic(caches)
+	}
+	if cache, ok := Lookup("users"); !ok || cache.Stats().Hits != 0 || cache.MaxSize() != 10 {
+		panic(ok)
+	}
+
+	Unregister("orders")
+	Unregister("missing")
+	if _, ok := Lookup("orders"); ok || len(Caches()) != 1 {
+		panic(Caches())
+	}
+
+	var r Registry
+	if len(r.Caches()) != 0 {
+		panic(r.Caches())
+	}
+}
+
+// 采集器通过 Monitored 读取的同时写入，在 -race 下检查读取持有锁
+func TestRegistry_Concurrent(t *testing.T) {
+	var r Registry
+	cache := New[int, int](100, nil, nil)
+	if err := r.Register("cache", cache); err != nil {
+		panic(err)
+	}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			cache.Put(i%50, i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			for _, named := range r.Caches() {
+				if named.Cache.Number() > 50 || named.Cache.Size() > 50 || named.Cache.MaxSize() != 100 {
+					panic(named.Cache.Number())
+				}
+			}
+		}
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,38 @@
+package lru
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Reserve 预留 size 大小的空间：立即淘汰到 Size() + 已预留的空间不超过 maxSize，并在 release 之前按减小后的容量淘汰
+// 用于批量写入前提前腾出空间，避免写入过程中边写边淘汰，也防止准备数据期间其他写入重新占满缓存。
+// 多个预留相互累加。WithMinResidency 保护的项不会被淘汰，此时预留的空间可能暂时不足。
+// size <= 0 时返回 ErrInvalidSize，预留总量超过 maxSize 时返回 ErrTooLarge。release 可以重复调用
+func (c *Cache[K, V]) Reserve(size int) (release func(), err error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: reserve %d", ErrInvalidSize, size)
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.reserved+size > c.maxSize {
+		return nil, fmt.Errorf("%w: reserve %d, reserved %d, max size %d", ErrTooLarge, size, c.reserved, c.maxSize)
+	}
+	c.reserved += size
+	c.expireUnlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.lock.Lock()
+			defer c.lock.Unlock()
+			c.reserved -= size
+			c.signalFreedUnlock()
+		})
+	}, nil
+}
+
+// limitUnlock 扣除 Reserve 预留之后的容量
+func (c *Cache[K, V]) limitUnlock() int {
+	return c.maxSize - c.reserved
+}
@@ -0,0 +1,62 @@
+package lru
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_Reserve(t *testing.T) {
+	cache := New[int, int](5, nil, nil)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	release, err := cache.Reserve(2)
+	if err != nil {
+		panic(err)
+	}
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []int{4, 3, 2}) {
+		panic(keys)
+	}
+	// 预留期间其他写入不能占用预留的空间
+	cache.Put(5, 5)
+	if cache.Number() != 3 {
+		panic(cache.AllKeys())
+	}
+	if _, err := cache.Reserve(4); !errors.Is(err, ErrTooLarge) {
+		panic(err)
+	}
+	if _, err := cache.Reserve(0); !errors.Is(err, ErrInvalidSize) {
+		panic(err)
+	}
+
+	// PutWait 等待预留释放
+	protected := New[int, int](2, nil, nil)
+	protected.Put(1, 1)
+	protected.Put(2, 2)
+	releaseAll, _ := protected.Reserve(2)
+	done := make(chan error)
+	go func() { done <- protected.PutWait(context.Background(), 3, 3) }()
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case err := <-done:
+		panic(err)
+	default:
+	}
+	releaseAll()
+	if err := <-done; err != nil {
+		panic(err)
+	}
+
+	release()
+	release()
+	for i := 6; i < 8; i++ {
+		cache.Put(i, i)
+	}
+	t.Log(cache.AllKeys())
+	if cache.Number() != 5 {
+		panic(cache.AllKeys())
+	}
+}
@@ -0,0 +1,17 @@
+package lru
+
+import "time"
+
+// WithMinResidency 保证缓存项写入后 d 时间内不会因容量不足或配额被淘汰，Remove 等显式移除不受影响
+// 用于突发流量时防止新写入的项还没来得及被再次访问就被挤出。所有项都受保护时缓存大小可能暂时超过 maxSize，
+// 直到有项超过保护期后的下一次写入时才淘汰
+func WithMinResidency[K comparable, V interface{}](d time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.minResidency = d
+	}
+}
+
+// protected 缓存项是否仍在最短驻留保护期内
+func (c *Cache[K, V]) protected(e *Entry[K, V], now time.Time) bool {
+	return c.minResidency > 0 && now.Sub(e.createTime) < c.minResidency
+}
@@ -0,0 +1,32 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_WithMinResidency(t *testing.T) {
+	cache := New[int, int](2, nil, nil, WithMinResidency[int, int](50*time.Millisecond))
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Put(3, 3) // 都在保护期内，暂时超出容量
+	if cache.Number() != 3 {
+		panic(cache.AllKeys())
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	cache.Put(4, 4) // 1 2 3 已过保护期，4 受保护
+	if !reflect.DeepEqual(cache.AllKeys(), []int{4, 3}) {
+		panic(cache.AllKeys())
+	}
+
+	if !reflect.DeepEqual(cache.EvictionOrder(), []int{3, 4}) {
+		panic(cache.EvictionOrder())
+	}
+
+	cache.Remove(4) // 显式移除不受保护
+	if cache.Number() != 1 {
+		panic(cache.AllKeys())
+	}
+}
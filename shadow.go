@@ -0,0 +1,54 @@
+package lru
+
+// WithShadow 维护一个只有 key 和大小的影子缓存，容量为 maxSize，接收与缓存相同的访问序列
+// 影子缓存的假想命中数见 Stats().ShadowHits 和 ShadowMisses，用于在线评估调整容量的效果而不影响真实缓存。
+// 目前只有 LRU 一种淘汰策略，影子缓存也使用 LRU
+func WithShadow[K comparable, V interface{}](maxSize int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.shadowSize = maxSize
+	}
+}
+
+// shadowState 影子缓存及其命中统计
+type shadowState[K comparable] struct {
+	cache  *ghostList[K]
+	hits   uint64
+	misses uint64
+}
+
+// shadowUnlock 返回影子缓存，容量变化时重建
+func (c *Cache[K, V]) shadowUnlock() *ghostList[K] {
+	if c.shadowSize <= 0 {
+		c.shadow.cache = nil
+		return nil
+	}
+	if c.shadow.cache == nil || c.shadow.cache.maxSize != c.shadowSize {
+		c.shadow.cache = newGhostList[K](c.shadowSize)
+	}
+	return c.shadow.cache
+}
+
+// shadowGetUnlock 在影子缓存中记录一次 Get
+func (c *Cache[K, V]) shadowGetUnlock(key K) {
+	if shadow := c.shadowUnlock(); shadow != nil {
+		if shadow.touch(key) {
+			c.shadow.hits++
+		} else {
+			c.shadow.misses++
+		}
+	}
+}
+
+// shadowPutUnlock 在影子缓存中记录一次写入
+func (c *Cache[K, V]) shadowPutUnlock(key K, value V) {
+	if shadow := c.shadowUnlock(); shadow != nil {
+		shadow.add(key, c.sizeOf(key, value))
+	}
+}
+
+// shadowRemoveUnlock 主动移除或过期时同步移除影子缓存中的 key，容量淘汰由影子缓存自己决定
+func (c *Cache[K, V]) shadowRemoveUnlock(key K) {
+	if c.shadow.cache != nil {
+		c.shadow.cache.remove(key)
+	}
+}
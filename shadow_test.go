@@ -0,0 +1,28 @@
+package lru
+
+import "testing"
+
+func TestCache_WithShadow(t *testing.T) {
+	cache := New[int, int](2, nil, nil, WithShadow[int, int](4))
+	access := func(key int) {
+		if _, ok := cache.Get(key); !ok {
+			cache.Put(key, key)
+		}
+	}
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 4; i++ {
+			access(i)
+		}
+	}
+	stats := cache.Stats()
+	// 真实缓存循环访问 4 个 key 全部未命中，影子缓存第一轮之后全部命中
+	if stats.Hits != 0 || stats.ShadowHits != 8 || stats.ShadowMisses != 4 {
+		panic(stats)
+	}
+
+	cache.Remove(3)
+	access(3)
+	if cache.Stats().ShadowMisses != 5 {
+		panic(cache.Stats())
+	}
+}
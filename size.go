@@ -0,0 +1,60 @@
+package lru
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidSize SizeStrict 策略下 sizeCal 返回 <= 0 时 TryPut 返回的错误
+var ErrInvalidSize = errors.New("lru: invalid entry size")
+
+// SizePolicy sizeCal 返回 0 或负数时的处理策略
+type SizePolicy int
+
+const (
+	// SizeAllowZero 默认策略，允许大小为 0 的缓存项，负数视为 0
+	SizeAllowZero SizePolicy = iota
+	// SizeClampToOne <= 0 的大小视为 1
+	SizeClampToOne
+	// SizeStrict 拒绝写入大小 <= 0 的缓存项，TryPut 返回 ErrInvalidSize，Put 和 PutWithTTL 忽略这次写入
+	SizeStrict
+)
+
+// WithSizePolicy 设置 sizeCal 返回 0 或负数时的处理策略，默认 SizeAllowZero
+// 负数大小不会再计入 curSize，避免 curSize 失真导致淘汰失效
+func WithSizePolicy[K comparable, V interface{}](policy SizePolicy) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.sizePolicy = policy
+	}
+}
+
+// sizeOf 按照 SizePolicy 计算缓存项大小，结果总是 >= 0
+func (c *Cache[K, V]) sizeOf(key K, value V) int {
+	size := c.sizeCal(key, value)
+	if size > 0 {
+		return size
+	}
+	if c.sizePolicy == SizeClampToOne {
+		return 1
+	}
+	return 0
+}
+
+// checkSize SizeStrict 策略下检查缓存项大小
+func (c *Cache[K, V]) checkSize(key K, value V) error {
+	if c.sizePolicy != SizeStrict {
+		return nil
+	}
+	if size := c.sizeCal(key, value); size <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidSize, size)
+	}
+	return nil
+}
+
+// TryPut 同 PutWithTTL，但 SizeStrict 策略下大小不合法、或 key 未通过 WithKeyValidator 的校验时返回错误且不写入
+func (c *Cache[K, V]) TryPut(key K, value V, ttl time.Duration, opts ...PutOption) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.tryPutUnlock(key, value, ttl, opts...)
+}
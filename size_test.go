@@ -0,0 +1,39 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCache_SizePolicy(t *testing.T) {
+	sizeCal := func(key int, value int) int { return value }
+
+	cache := New[int, int](3, nil, sizeCal)
+	cache.Put(1, -5)
+	cache.Put(2, 2)
+	cache.Put(3, 2) // 负数视为 0，淘汰仍然有效
+	if cache.Size() != 2 || cache.Number() != 1 {
+		panic(cache.AllKeys())
+	}
+
+	cache = New[int, int](3, nil, sizeCal, WithSizePolicy[int, int](SizeClampToOne))
+	cache.Put(1, 0)
+	cache.Put(2, -1)
+	if cache.Size() != 2 {
+		panic(cache.Size())
+	}
+
+	cache = New[int, int](3, nil, sizeCal, WithSizePolicy[int, int](SizeStrict))
+	if err := cache.TryPut(1, 0, 0); !errors.Is(err, ErrInvalidSize) {
+		panic(err)
+	}
+	cache.Put(2, -1)
+	if err := cache.TryPut(3, 1, 0); err != nil {
+		panic(err)
+	}
+	if !reflect.DeepEqual(cache.AllKeys(), []int{3}) {
+		panic(cache.AllKeys())
+	}
+	t.Log(cache.checkSize(1, 0))
+}
@@ -0,0 +1,155 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"time"
+)
+
+// SnapshotView 只读打开的快照，文件通过 mmap 映射（不支持 mmap 的平台上整体读入），value 在访问时才解码
+// 用于工具进程检查或对外提供大的缓存镜像，而不必把全部 value 载入堆。打开时只解码 key 建立索引。
+// 方法可以并发调用，Close 之后不能再使用
+type SnapshotView[K comparable, V interface{}] struct {
+	data  []byte
+	unmap func() error
+	aead  cipher.AEAD
+	index map[K]viewEntry
+	keys  []K // 最久未访问到最近访问
+}
+
+// viewEntry 一条记录在映射中的位置
+type viewEntry struct {
+	offset   int // 负载的起始位置
+	length   int
+	expireAt time.Time
+	stale    bool // 保存时已被 Invalidate
+}
+
+// viewKey 只解码 key、过期时间和失效标记，gob 跳过 Value 字段
+type viewKey[K comparable] struct {
+	Op       byte
+	Key      K
+	ExpireAt time.Time
+	Stale    bool
+}
+
+// OpenSnapshotView 只读打开 SaveToFile 写出的快照，key 为 WithSnapshotEncryption 的密钥，快照未加密时忽略
+// 快照损坏时返回 ErrCorrupted，格式版本不一致时返回 ErrVersionMismatch，无法解密时返回 ErrDecrypt
+func OpenSnapshotView[K comparable, V interface{}](path string, key []byte) (*SnapshotView[K, V], error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	v := &SnapshotView[K, V]{data: data, unmap: unmap, index: map[K]viewEntry{}}
+	if err = v.build(aead); err != nil {
+		_ = unmap()
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *SnapshotView[K, V]) build(aead cipher.AEAD) error {
+	flags, err := readHeader(bytes.NewReader(v.data), snapshotMagic)
+	if err != nil {
+		return fmt.Errorf("%w: missing header", ErrCorrupted)
+	}
+	if flags&flagEncrypted != 0 {
+		if aead == nil {
+			return fmt.Errorf("%w: snapshot is encrypted but no key configured", ErrDecrypt)
+		}
+		v.aead = aead
+	}
+	for offset := headerSize; offset < len(v.data); {
+		if len(v.data)-offset < recordHeaderSize {
+			return fmt.Errorf("%w: truncated record", ErrCorrupted)
+		}
+		length := int(binary.BigEndian.Uint32(v.data[offset : offset+4]))
+		sum := binary.BigEndian.Uint32(v.data[offset+4 : offset+8])
+		offset += recordHeaderSize
+		if length > maxRecordSize || len(v.data)-offset < length {
+			return fmt.Errorf("%w: truncated record", ErrCorrupted)
+		}
+		if crc32.ChecksumIEEE(v.data[offset:offset+length]) != sum {
+			return fmt.Errorf("%w: checksum mismatch", ErrCorrupted)
+		}
+		var rec viewKey[K]
+		if err := v.decode(offset, length, &rec); err != nil {
+			return err
+		}
+		if rec.Op == opPut {
+			if _, ok := v.index[rec.Key]; !ok {
+				v.keys = append(v.keys, rec.Key)
+			}
+			v.index[rec.Key] = viewEntry{offset: offset, length: length, expireAt: rec.ExpireAt, stale: rec.Stale}
+		}
+		offset += length
+	}
+	return nil
+}
+
+// decode 解密并解码一条记录的负载
+func (v *SnapshotView[K, V]) decode(offset, length int, out interface{}) error {
+	payload := v.data[offset : offset+length]
+	if v.aead != nil {
+		var err error
+		if payload, err = unseal(v.aead, payload); err != nil {
+			return err
+		}
+	}
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(out); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupted, err)
+	}
+	return nil
+}
+
+// Get 解码并返回 key 对应的 value，key 不存在、已过期或已失效时返回 false，与 Cache.Get 一致。用 Stale 区分失效
+func (v *SnapshotView[K, V]) Get(key K) (value V, ok bool, err error) {
+	e, ok := v.index[key]
+	if !ok || e.stale || (!e.expireAt.IsZero() && !time.Now().Before(e.expireAt)) {
+		return value, false, nil
+	}
+	var rec record[K, V]
+	if err := v.decode(e.offset, e.length, &rec); err != nil {
+		return value, false, err
+	}
+	return rec.Value, true, nil
+}
+
+// ExpireAt 返回 key 的过期时间，零值表示永不过期，不解码 value
+func (v *SnapshotView[K, V]) ExpireAt(key K) (time.Time, bool) {
+	e, ok := v.index[key]
+	return e.expireAt, ok
+}
+
+// Stale 判断 key 在保存快照时是否已被 Invalidate，不解码 value
+func (v *SnapshotView[K, V]) Stale(key K) bool {
+	return v.index[key].stale
+}
+
+// Keys 返回快照中的全部 key，包括已过期的，按最久未访问到最近访问的顺序
+func (v *SnapshotView[K, V]) Keys() []K {
+	return append([]K(nil), v.keys...)
+}
+
+// Len 返回快照中 key 的数目，包括已过期的
+func (v *SnapshotView[K, V]) Len() int {
+	return len(v.keys)
+}
+
+// Close 解除映射
+func (v *SnapshotView[K, V]) Close() error {
+	v.index = nil
+	v.keys = nil
+	v.data = nil
+	return v.unmap()
+}
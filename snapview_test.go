@@ -0,0 +1,82 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSnapshotView(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cache.snapshot")
+	cache := New[string, []int](10, nil, nil)
+	cache.Put("a", []int{1})
+	cache.PutWithTTL("b", []int{2, 2}, time.Hour)
+	cache.Put("c", []int{3, 3, 3})
+	cache.Get("a")
+	cache.Put("d", []int{4})
+	cache.Invalidate("d")
+	if err := cache.SaveToFile(path); err != nil {
+		panic(err)
+	}
+
+	view, err := OpenSnapshotView[string, []int](path, nil)
+	if err != nil {
+		panic(err)
+	}
+	if keys := view.Keys(); !reflect.DeepEqual(keys, []string{"b", "c", "a", "d"}) || view.Len() != 4 {
+		panic(keys)
+	}
+	value, ok, err := view.Get("c")
+	if err != nil || !ok || !reflect.DeepEqual(value, []int{3, 3, 3}) {
+		panic(value)
+	}
+	if _, ok, _ := view.Get("missing"); ok {
+		panic("missing")
+	}
+	// 已失效的项按未命中处理
+	if _, ok, _ := view.Get("d"); ok || !view.Stale("d") || view.Stale("c") {
+		panic("stale")
+	}
+	if expireAt, ok := view.ExpireAt("b"); !ok || expireAt.IsZero() {
+		panic(expireAt)
+	}
+	if err := view.Close(); err != nil {
+		panic(err)
+	}
+
+	// 加密
+	key := []byte("0123456789abcdef")
+	encrypted := New[string, []int](10, nil, nil, WithSnapshotEncryption[string, []int](key))
+	encrypted.Put("x", []int{9})
+	encPath := filepath.Join(dir, "encrypted.snapshot")
+	if err := encrypted.SaveToFile(encPath); err != nil {
+		panic(err)
+	}
+	if _, err := OpenSnapshotView[string, []int](encPath, nil); !errors.Is(err, ErrDecrypt) {
+		panic(err)
+	}
+	view, err = OpenSnapshotView[string, []int](encPath, key)
+	if err != nil {
+		panic(err)
+	}
+	defer view.Close()
+	if value, ok, err := view.Get("x"); err != nil || !ok || value[0] != 9 {
+		panic(value)
+	}
+
+	// 损坏
+	data, _ := os.ReadFile(path)
+	badPath := filepath.Join(dir, "bad.snapshot")
+	_ = os.WriteFile(badPath, data[:len(data)-1], 0o644)
+	_, err = OpenSnapshotView[string, []int](badPath, nil)
+	t.Log(err)
+	if !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+}
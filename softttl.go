@@ -0,0 +1,51 @@
+package lru
+
+import "time"
+
+// putOptions 单次写入的可选配置
+type putOptions struct {
+	softTTL time.Duration
+}
+
+// PutOption PutWithTTL、TryPut 和 Do 的单次写入配置
+type PutOption func(o *putOptions)
+
+// SoftTTL 设置软过期时长，常见于 CDN 类缓存的 stale-while-revalidate
+// 超过软过期后 Get 仍然命中，Do 命中时返回旧值并在后台调用加载函数刷新；超过 PutWithTTL 的 ttl（硬过期）后才是未命中。
+// d <= 0 或不小于硬过期时长时没有效果。软过期时间不写入快照和预写日志
+func SoftTTL(d time.Duration) PutOption {
+	return func(o *putOptions) {
+		o.softTTL = d
+	}
+}
+
+// softExpireUnlock 写入后按 opts 设置软过期时间
+func (c *Cache[K, V]) softExpireUnlock(key K, opts []PutOption) {
+	if len(opts) == 0 {
+		return
+	}
+	var o putOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ele, ok := c.m[key]
+	if !ok || o.softTTL <= 0 {
+		return
+	}
+	e := ele.Value.(*Entry[K, V])
+	e.softAt = time.Time{}
+	if softAt := time.Now().Add(o.softTTL); e.expireAt.IsZero() || softAt.Before(e.expireAt) {
+		e.softAt = softAt
+	}
+}
+
+// GetWithExpiration 同 Get，同时返回软过期和硬过期时间，零值表示没有设置
+func (c *Cache[K, V]) GetWithExpiration(key K) (value V, softExpireAt, expireAt time.Time, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if value, ok = c.getUnlock(key); !ok {
+		return value, softExpireAt, expireAt, false
+	}
+	e := c.m[c.resolveUnlock(key)].Value.(*Entry[K, V])
+	return value, e.softAt, e.expireAt, true
+}
@@ -0,0 +1,59 @@
+package lru
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_SoftTTL(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+	cache.PutWithTTL("k", 1, time.Hour, SoftTTL(10*time.Millisecond))
+	_, softAt, expireAt, ok := cache.GetWithExpiration("k")
+	if !ok || softAt.IsZero() || !softAt.Before(expireAt) {
+		panic(softAt)
+	}
+
+	// 软过期后 Get 仍然命中，Do 返回旧值并在后台刷新
+	time.Sleep(15 * time.Millisecond)
+	if value, ok := cache.Get("k"); !ok || value != 1 {
+		panic(value)
+	}
+	var loads atomic.Int32
+	load := func() (int, error) {
+		loads.Add(1)
+		return 2, nil
+	}
+	if value, _ := cache.Do("k", load, time.Hour, SoftTTL(30*time.Minute)); value != 1 {
+		panic(value)
+	}
+	for cache.Refreshing() != 0 || loads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	value, softAt, _, _ := cache.GetWithExpiration("k")
+	if value != 2 || time.Until(softAt) < time.Minute {
+		panic(value)
+	}
+
+	// 硬过期后未命中
+	cache.PutWithTTL("h", 1, time.Millisecond, SoftTTL(time.Hour)) // 软过期不晚于硬过期时无效
+	if _, softAt, _, _ = cache.GetWithExpiration("h"); !softAt.IsZero() {
+		panic(softAt)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, ok := cache.Get("h"); ok {
+		panic("hard expired")
+	}
+}
+
+func TestCache_GetWithExpiration_Alias(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+	cache.PutWithTTL("a", 1, time.Hour)
+	if !cache.Alias("b", "a") {
+		panic("Alias")
+	}
+	value, _, expireAt, ok := cache.GetWithExpiration("b")
+	if !ok || value != 1 || expireAt.IsZero() {
+		panic(value)
+	}
+}
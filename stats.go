@@ -0,0 +1,90 @@
+package lru
+
+import "time"
+
+// HistogramBounds 直方图各桶的上界，最后还有一个 +Inf 桶
+var HistogramBounds = [...]time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	24 * time.Hour,
+}
+
+// Histogram 时长直方图
+// Counts[i] 为落在 (HistogramBounds[i-1], HistogramBounds[i]] 内的样本数，Counts 最后一项为超出所有上界的样本数
+type Histogram struct {
+	Counts [len(HistogramBounds) + 1]uint64
+	Count  uint64
+	Sum    time.Duration
+}
+
+func (h *Histogram) observe(d time.Duration) {
+	i := 0
+	for i < len(HistogramBounds) && d > HistogramBounds[i] {
+		i++
+	}
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// Mean 返回样本平均值，没有样本时返回 0
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Stats 缓存统计信息
+// Lifetime 和 Idle 只统计因容量不足被淘汰的项，不包括 Remove 等主动移除。
+// 如果淘汰项的 Lifetime 普遍很短，或者 Idle 远小于数据本身的复用周期，说明缓存过小
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Lifetime  Histogram // 从写入到被淘汰的时长
+	Idle      Histogram // 被淘汰时距最近一次访问的时长
+
+	ExpireDropped uint64 // WithExpireRateLimit 限速时被丢弃的失效回调数目
+	GhostHits     uint64 // WithAdaptiveCapacity 时未命中但命中幽灵列表的次数
+	ShadowHits    uint64 // WithShadow 影子缓存的假想命中数
+	ShadowMisses  uint64 // WithShadow 影子缓存的假想未命中数
+	DecayEpoch    uint64 // WithFrequencyDecay 已衰减的周期数
+	VictimHits    uint64 // WithVictimCache 未命中但在受害者缓存中找到的次数，已计入 Hits
+
+	Circuit      CircuitState // WithCircuitBreaker 熔断器当前状态
+	CircuitOpens uint64       // WithCircuitBreaker 累计熔断次数
+
+	Churn ChurnStats // WithChurnWindow 最近的变化速率
+}
+
+// Stats 返回统计信息快照
+func (c *Cache[K, V]) Stats() Stats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	stats := Stats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		Evictions:    c.evictions,
+		Lifetime:     c.lifetime,
+		Idle:         c.idle,
+		GhostHits:    c.adapt.total,
+		ShadowHits:   c.shadow.hits,
+		ShadowMisses: c.shadow.misses,
+		DecayEpoch:   c.decay.epoch,
+		VictimHits:   c.victims.hits,
+	}
+	stats.Circuit, stats.CircuitOpens = c.breaker.stats()
+	stats.Churn = c.churnStatsUnlock()
+	if c.expireLimiter != nil {
+		stats.ExpireDropped = c.expireLimiter.dropped.Load()
+	}
+	return stats
+}
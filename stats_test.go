@@ -0,0 +1,38 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache_Stats(t *testing.T) {
+	cache := New[int, int](5, nil, nil)
+	for i := 0; i < 10; i++ {
+		cache.Put(i, i)
+	}
+	_, _ = cache.Get(9)
+	_, _ = cache.GetNoMove(0)
+
+	stats := cache.Stats()
+	t.Log(stats)
+	if stats.Hits != 1 || stats.Misses != 1 {
+		panic(stats)
+	}
+	if stats.Evictions != 5 || stats.Lifetime.Count != 5 || stats.Idle.Count != 5 {
+		panic(stats)
+	}
+}
+
+func TestHistogram_observe(t *testing.T) {
+	var h Histogram
+	h.observe(0)
+	h.observe(time.Millisecond)
+	h.observe(2 * time.Millisecond)
+	h.observe(48 * time.Hour)
+	if h.Counts[0] != 2 || h.Counts[1] != 1 || h.Counts[len(h.Counts)-1] != 1 {
+		panic(h.Counts)
+	}
+	if h.Count != 4 {
+		panic(h.Count)
+	}
+}
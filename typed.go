@@ -0,0 +1,67 @@
+package lru
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// GetAs 同 Get，并把 value 断言为 T，用于保存多种类型 value 的 Cache[K, interface{}]
+// key 不存在或 value 不是 T 时返回 false，类型不符时不 panic，但仍计为一次命中
+func GetAs[T interface{}, K comparable](c *Cache[K, interface{}], key K) (T, bool) {
+	value, ok := c.Get(key)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	t, ok := value.(T)
+	return t, ok
+}
+
+// PutTyped 同 PutWithTTL，并记录写入时的类型 T，供 ValueTypes 等调试接口使用
+// T 为接口类型时记录的是接口类型本身，例如 PutTyped[error] 记录 error 而不是具体的错误类型
+func PutTyped[T interface{}, K comparable](c *Cache[K, interface{}], key K, value T, ttl time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key = c.resolveUnlock(key)
+	if c.tryPutUnlock(key, value, ttl) != nil {
+		return
+	}
+	if _, ok := c.m[key]; !ok {
+		return // 写入后立刻被淘汰
+	}
+	if c.valueTypes == nil {
+		c.valueTypes = map[K]reflect.Type{}
+	}
+	c.valueTypes[key] = reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// ValueType 返回 key 的 value 类型：PutTyped 写入的返回记录的类型，否则返回 value 的动态类型
+func (c *Cache[K, V]) ValueType(key K) (string, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	key = c.resolveUnlock(key)
+	ele, ok := c.m[key]
+	if !ok {
+		return "", false
+	}
+	return c.valueTypeUnlock(key, ele.Value.(*Entry[K, V]).value), true
+}
+
+// ValueTypes 按类型统计缓存项的数目，类型的取法同 ValueType
+func (c *Cache[K, V]) ValueTypes() map[string]int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	types := map[string]int{}
+	for key, ele := range c.m {
+		types[c.valueTypeUnlock(key, ele.Value.(*Entry[K, V]).value)]++
+	}
+	return types
+}
+
+func (c *Cache[K, V]) valueTypeUnlock(key K, value V) string {
+	if t, ok := c.valueTypes[key]; ok {
+		return t.String()
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,46 @@
+package lru
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetAs(t *testing.T) {
+	cache := New[string, interface{}](10, nil, nil)
+	PutTyped(cache, "n", 1, 0)
+	PutTyped[error](cache, "err", errors.New("boom"), 0)
+	cache.Put("s", "text")
+
+	if n, ok := GetAs[int](cache, "n"); !ok || n != 1 {
+		panic(n)
+	}
+	if _, ok := GetAs[string](cache, "n"); ok {
+		panic("n is not a string")
+	}
+	if err, ok := GetAs[error](cache, "err"); !ok || err.Error() != "boom" {
+		panic(err)
+	}
+	if _, ok := GetAs[int](cache, "missing"); ok {
+		panic("missing")
+	}
+
+	if typ, ok := cache.ValueType("err"); !ok || typ != "error" {
+		panic(typ)
+	}
+	types := cache.ValueTypes()
+	t.Log(types)
+	if !reflect.DeepEqual(types, map[string]int{"int": 1, "error": 1, "string": 1}) {
+		panic(types)
+	}
+
+	// 普通写入覆盖后不再使用记录的类型
+	cache.Put("err", "fixed")
+	if typ, _ := cache.ValueType("err"); typ != "string" {
+		panic(typ)
+	}
+	cache.Remove("n")
+	if len(cache.valueTypes) != 0 {
+		panic(cache.valueTypes)
+	}
+}
@@ -0,0 +1,77 @@
+package lru
+
+import "time"
+
+// Unlocked 持有缓存写锁时可以调用的操作，只在 WithLock、ScanWithLock、AddLockedListener 和 WithLockedExpireCallback 的回调中有效，不能保存到回调之外使用
+type Unlocked[K comparable, V interface{}] struct {
+	c *Cache[K, V]
+}
+
+// WithLock 持有写锁调用 fn，fn 中通过 u 对缓存进行多次操作，整体是原子的，例如先读后写、检查后移除
+// fn 中不能调用 Cache 的方法，否则死锁，只能使用 u。u 上的操作与 Cache 的同名方法行为一致，
+// 包括失效回调、监听器和预写日志。在回调中读写缓存见 ScanWithLock、AddLockedListener 和 WithLockedExpireCallback
+func (c *Cache[K, V]) WithLock(fn func(u Unlocked[K, V])) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	fn(Unlocked[K, V]{c: c})
+}
+
+// ScanWithLock 同 Scan，但持有写锁，consumer 可以通过 u 读写缓存
+// 遍历开始时的缓存项依次交给 consumer，期间被移除或覆盖的项跳过，新写入的项不会遍历到
+func (c *Cache[K, V]) ScanWithLock(consumer func(u Unlocked[K, V], key K, value V) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var entries []*Entry[K, V]
+	c.rangeUnlock(func(e *Entry[K, V]) bool {
+		entries = append(entries, e)
+		return true
+	})
+	for _, e := range entries {
+		if ele, ok := c.m[e.key]; !ok || ele.Value.(*Entry[K, V]) != e {
+			continue
+		}
+		if !consumer(Unlocked[K, V]{c: c}, e.key, e.value) {
+			return
+		}
+	}
+}
+
+// WithLockedExpireCallback 设置失效回调，回调在持有缓存写锁时调用，可以通过 u 读写缓存，例如把失效项降级写入另一个 key
+// 与 WithExpireCallback 和 New 的 expireCallback 互相覆盖，后设置的生效。u 上的写操作可能同步触发新的失效回调
+func WithLockedExpireCallback[K comparable, V interface{}](expireCallback func(u Unlocked[K, V], key K, value V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onExpire = nil
+		o.onExpireLocked = expireCallback
+	}
+}
+
+func (u Unlocked[K, V]) Get(key K) (value V, ok bool) {
+	return u.c.getUnlock(key)
+}
+
+func (u Unlocked[K, V]) Put(key K, value V) {
+	_ = u.c.tryPutUnlock(key, value, 0)
+}
+
+func (u Unlocked[K, V]) PutWithTTL(key K, value V, ttl time.Duration, opts ...PutOption) {
+	_ = u.c.tryPutUnlock(key, value, ttl, opts...)
+}
+
+func (u Unlocked[K, V]) Remove(key K) {
+	u.c.removeUnlock(u.c.resolveUnlock(key), EventRemove)
+}
+
+// Contains 判断 key 是否存在且未过期、未失效，不修改访问顺序和统计信息
+func (u Unlocked[K, V]) Contains(key K) bool {
+	ele, ok := u.c.m[u.c.resolveUnlock(key)]
+	return ok && !ele.Value.(*Entry[K, V]).stale && !ele.Value.(*Entry[K, V]).expired(time.Now())
+}
+
+func (u Unlocked[K, V]) Size() int {
+	return u.c.curSize
+}
+
+func (u Unlocked[K, V]) Number() int {
+	return u.c.li.Len()
+}
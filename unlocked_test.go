@@ -0,0 +1,96 @@
+package lru
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCache_WithLock(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+
+	// 并发的读改写不会丢失更新
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.WithLock(func(u Unlocked[string, int]) {
+				value, _ := u.Get("counter")
+				u.Put("counter", value+1)
+			})
+		}()
+	}
+	wg.Wait()
+	if value, _ := cache.Get("counter"); value != 50 {
+		panic(value)
+	}
+
+	// 检查后移除
+	cache.WithLock(func(u Unlocked[string, int]) {
+		if u.Contains("counter") && u.Number() == 1 {
+			u.Remove("counter")
+		}
+	})
+	if cache.Number() != 0 {
+		panic(cache.Number())
+	}
+}
+
+func TestCache_AddLockedListener(t *testing.T) {
+	cache := New[string, int](2, nil, nil)
+	// 同步监听中调用 Put、Remove 不会死锁
+	cache.AddLockedListener(func(u Unlocked[string, int], event Event[string, int]) {
+		if event.Type == EventPut && event.Key == "a" {
+			u.Put("mirror", event.Value)
+		}
+		if event.Type == EventEvict {
+			u.Remove("a")
+		}
+	})
+	cache.Put("a", 1)
+	if value, ok := cache.Get("mirror"); !ok || value != 1 {
+		panic(cache.AllKeys())
+	}
+	cache.Put("b", 2) // 淘汰 a，监听中移除不存在的 a 无副作用
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"b", "mirror"}) {
+		panic(keys)
+	}
+}
+
+func TestCache_ScanWithLock(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i)
+	}
+	var scanned []int
+	cache.ScanWithLock(func(u Unlocked[int, int], key int, value int) bool {
+		scanned = append(scanned, key)
+		if key%2 == 0 {
+			u.Remove(key - 1) // 移除尚未遍历到的项
+		}
+		u.Put(key+100, value) // 新写入的项不会遍历到
+		return true
+	})
+	t.Log(scanned)
+	if !reflect.DeepEqual(scanned, []int{4, 2, 0}) {
+		panic(scanned)
+	}
+	if cache.Number() != 6 {
+		panic(cache.AllKeys())
+	}
+}
+
+func TestCache_WithLockedExpireCallback(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithLockedExpireCallback(func(u Unlocked[string, int], key string, value int) {
+		if key != "fallback" {
+			u.Put("fallback", value)
+		}
+	}))
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Remove("a")
+	if value, ok := cache.Get("fallback"); !ok || value != 1 {
+		panic(cache.AllKeys())
+	}
+}
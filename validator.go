@@ -0,0 +1,46 @@
+package lru
+
+import (
+	"context"
+	"time"
+)
+
+// WithValidator Do 遇到已过期的项时先调用 validator 校验旧值，仍然有效则按 ttl 延长有效期并返回旧值，不调用加载函数
+// 适合 value 很大而变化很少、可以用版本号等廉价方式确认是否变化的数据。validator 返回 false 或错误时正常加载。
+// 校验与加载一样对同一 key 只执行一次。为了保留旧值，开启后 Do 遇到的过期项不会被移除。Invalidate 标记的失效项不校验
+func WithValidator[K comparable, V interface{}](validator func(ctx context.Context, key K, value V) (stillValid bool, err error)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.validator = validator
+	}
+}
+
+// revalidate 校验过期的旧值，有效时延长有效期并返回 true
+func (c *Cache[K, V]) revalidate(ctx context.Context, key K, stale *staleValue[K, V], ttl time.Duration) bool {
+	if stale == nil || !stale.expired || stale.validator == nil {
+		return false
+	}
+	if valid, err := stale.validator(ctx, key, stale.value); err != nil || !valid {
+		return false
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key = c.resolveUnlock(key)
+	ele, ok := c.m[key]
+	if !ok || ele.Value.(*Entry[K, V]) != stale.entry { // 校验期间已被移除或覆盖
+		return true
+	}
+	if ttl <= 0 && c.expiry != nil {
+		ttl = c.expiry(key, stale.value)
+	}
+	now := time.Now()
+	e := stale.entry
+	e.expireAt = time.Time{}
+	if ttl > 0 {
+		e.expireAt = now.Add(ttl)
+	}
+	e.accessTime = now
+	c.li.MoveToFront(ele)
+	c.logUnlock(opPut, key, e.value, e.expireAt)
+	return true
+}
@@ -0,0 +1,68 @@
+package lru
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_WithValidator(t *testing.T) {
+	version := 1
+	validations := 0
+	cache := New[string, int](10, nil, nil, WithValidator(func(ctx context.Context, key string, value int) (bool, error) {
+		validations++
+		return value == version, nil
+	}))
+	loads := 0
+	load := func(ctx context.Context) (int, error) {
+		loads++
+		return version, nil
+	}
+
+	if value, _ := cache.DoContext(context.Background(), "k", load, 10*time.Millisecond); value != 1 || loads != 1 {
+		panic(value)
+	}
+
+	// 过期后校验通过，延长有效期，不重新加载
+	time.Sleep(15 * time.Millisecond)
+	if value, _ := cache.DoContext(context.Background(), "k", load, time.Hour); value != 1 || loads != 1 || validations != 1 {
+		panic(loads)
+	}
+	if value, ok := cache.Get("k"); !ok || value != 1 {
+		panic("not extended")
+	}
+
+	// 版本变化，校验失败后重新加载
+	cache.PutWithTTL("k", 1, time.Millisecond)
+	version = 2
+	time.Sleep(2 * time.Millisecond)
+	if value, _ := cache.Do("k", func() (int, error) { return load(context.Background()) }, time.Hour); value != 2 || loads != 2 || validations != 2 {
+		panic(value)
+	}
+
+	// 失效的项不校验
+	cache.Invalidate("k")
+	cache.Do("k", func() (int, error) { return load(context.Background()) }, time.Hour)
+	if validations != 2 || loads != 3 {
+		panic(validations)
+	}
+}
+
+func TestCache_WithValidator_Alias(t *testing.T) {
+	cache := New[string, int](10, nil, nil, WithValidator(func(ctx context.Context, key string, value int) (bool, error) {
+		return true, nil
+	}))
+	cache.PutWithTTL("a", 1, 10*time.Millisecond)
+	if !cache.Alias("b", "a") {
+		panic("Alias")
+	}
+	time.Sleep(15 * time.Millisecond)
+	load := func(ctx context.Context) (int, error) { return 2, nil }
+	if value, _ := cache.DoContext(context.Background(), "b", load, time.Hour); value != 1 {
+		panic(value)
+	}
+	// 校验通过后通过 alias 延长了 primary 的有效期
+	if value, ok := cache.Get("a"); !ok || value != 1 {
+		panic("not extended")
+	}
+}
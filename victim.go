@@ -0,0 +1,102 @@
+package lru
+
+import (
+	"container/list"
+	"time"
+)
+
+// WithVictimCache 被淘汰的项先进入一个容量为 size 的小 LRU（受害者缓存），保留完整的 value，size 与 maxSize 单位相同
+// Get 和 Do 未命中时先查找受害者缓存，找到则放回缓存并计为命中（见 Stats().VictimHits），不调用加载函数，
+// 以很低的代价吸收工作集短时间超出容量的情况。失效函数推迟到项离开受害者缓存时执行，放回缓存的项不执行。
+// 受害者缓存中的项对 AllKeys、Scan 等不可见，写入、Remove 同一 key 时丢弃。size <= 0 表示不开启
+func WithVictimCache[K comparable, V interface{}](size int) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.victimSize = size
+	}
+}
+
+// victimCache 受害者缓存，li 中为 *Entry，最近淘汰的在前
+type victimCache[K comparable, V interface{}] struct {
+	li   *list.List
+	m    map[K]*list.Element
+	size int
+	hits uint64
+}
+
+// victimAddUnlock 被淘汰的项进入受害者缓存，挤出的项执行失效函数
+func (c *Cache[K, V]) victimAddUnlock(e *Entry[K, V]) {
+	v := &c.victims
+	if v.li == nil {
+		v.li = list.New()
+		v.m = map[K]*list.Element{}
+	}
+	v.m[e.key] = v.li.PushFront(e)
+	v.size += c.sizeOf(e.key, e.value)
+	c.trimVictimsUnlock()
+}
+
+// trimVictimsUnlock 受害者缓存超出容量时挤出最早淘汰的项
+func (c *Cache[K, V]) trimVictimsUnlock() {
+	for v := &c.victims; v.size > c.victimSize && v.li.Len() > 0; {
+		c.victimDropUnlock(v.li.Back(), true)
+	}
+}
+
+// victimDropUnlock 从受害者缓存中移除，expire 指示是否执行失效函数
+func (c *Cache[K, V]) victimDropUnlock(ele *list.Element, expire bool) {
+	v := &c.victims
+	e := ele.Value.(*Entry[K, V])
+	v.li.Remove(ele)
+	delete(v.m, e.key)
+	v.size -= c.sizeOf(e.key, e.value)
+	if expire {
+		c.expireEntryUnlock(e)
+	}
+}
+
+// victimRemoveUnlock 丢弃受害者缓存中的 key 并执行失效函数
+func (c *Cache[K, V]) victimRemoveUnlock(key K) {
+	if ele, ok := c.victims.m[key]; ok {
+		c.victimDropUnlock(ele, true)
+	}
+}
+
+// victimGetUnlock 未命中时在受害者缓存中查找，找到未过期的项则放回缓存最前，命中统计由调用方完成
+func (c *Cache[K, V]) victimGetUnlock(key K) (*list.Element, bool) {
+	ele, ok := c.victims.m[key]
+	if !ok {
+		return nil, false
+	}
+	e := ele.Value.(*Entry[K, V])
+	if e.expired(time.Now()) {
+		c.victimDropUnlock(ele, true)
+		return nil, false
+	}
+	c.victimDropUnlock(ele, false)
+	restored := c.li.PushFront(e)
+	c.m[key] = restored
+	c.insertOrderUnlock(e)
+	if c.adapt.ghost != nil {
+		c.adapt.ghost.remove(key)
+	}
+	c.accountUnlock(key, c.sizeOf(key, e.value))
+	c.logUnlock(opPut, key, e.value, e.expireAt)
+	c.notifyUnlock(EventPut, key, e.value)
+	c.enforceQuotaUnlock(key)
+	c.expireUnlock()
+	if c.m[key] != restored { // 比整个缓存还大，又被淘汰
+		return nil, false
+	}
+	c.victims.hits++
+	return restored, true
+}
+
+// resetVictimsUnlock 清空受害者缓存，expire 指示是否执行失效函数
+func (c *Cache[K, V]) resetVictimsUnlock(expire bool) {
+	if expire {
+		for _, ele := range c.victims.m {
+			c.expireEntryUnlock(ele.Value.(*Entry[K, V]))
+		}
+	}
+	c.victims = victimCache[K, V]{hits: c.victims.hits}
+}
@@ -0,0 +1,68 @@
+package lru
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_WithVictimCache(t *testing.T) {
+	var expired []string
+	cache := New[string, int](2, func(key string, value int) { expired = append(expired, key) }, nil,
+		WithVictimCache[string, int](2))
+	cache.Put("a", 1)
+	cache.Put("b", 2)
+	cache.Put("c", 3) // a 进入受害者缓存
+	if len(expired) != 0 || !reflect.DeepEqual(cache.AllKeys(), []string{"c", "b"}) {
+		panic(expired)
+	}
+
+	// 命中受害者缓存，a 放回，b 被淘汰
+	if value, ok := cache.Get("a"); !ok || value != 1 {
+		panic(value)
+	}
+	if keys := cache.AllKeys(); !reflect.DeepEqual(keys, []string{"a", "c"}) {
+		panic(keys)
+	}
+	if stats := cache.Stats(); stats.VictimHits != 1 || stats.Hits != 1 {
+		panic(stats)
+	}
+
+	// Do 命中受害者缓存时不调用加载函数
+	value, err := cache.Do("b", func() (int, error) { panic("loader called") }, 0)
+	if err != nil || value != 2 {
+		panic(value)
+	}
+
+	// 挤出受害者缓存时执行失效函数
+	cache.Put("d", 4)
+	cache.Put("e", 5)
+	cache.Put("f", 6)
+	t.Log(cache.AllKeys(), expired)
+	if !reflect.DeepEqual(expired, []string{"c", "a"}) {
+		panic(expired)
+	}
+
+	// 写入和 Remove 丢弃受害者缓存中的同一 key
+	cache.Put("b", 22)
+	cache.Remove("d")
+	if !reflect.DeepEqual(expired, []string{"c", "a", "b", "d"}) {
+		panic(expired)
+	}
+	cache.RemoveAll()
+	if len(expired) != 7 { // 缓存中的 b、f 和受害者缓存中的 e
+		panic(expired)
+	}
+	if _, ok := cache.Get("b"); ok {
+		panic("b")
+	}
+
+	// 已过期的项不放回
+	ttl := New[string, int](1, nil, nil, WithVictimCache[string, int](1))
+	ttl.PutWithTTL("a", 1, 10*time.Millisecond)
+	ttl.Put("b", 2)
+	time.Sleep(15 * time.Millisecond)
+	if _, ok := ttl.Get("a"); ok {
+		panic("a")
+	}
+}
@@ -0,0 +1,290 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"crypto/cipher"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// wal 预写日志，Put/Remove 等修改操作在持有缓存写锁时追加写入
+type wal struct {
+	path         string
+	snapshotPath string
+	file         *os.File
+	aead         cipher.AEAD // 为空表示不加密
+	err          error       // 第一个写入错误
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+// OpenWAL 开启预写日志
+// 先加载快照 path + ".snapshot"，再重放日志 path，之后所有 Put/Remove/RemoveIf/RemoveAll 都追加写入日志，
+// 进程崩溃后重新调用 OpenWAL 即可恢复。因容量不足淘汰和过期移除同样写入删除记录，重放期间不按容量淘汰；
+// Get 调整访问先后不写日志，恢复后缓存内容不变，但访问先后按写入先后。
+// 日志尾部不完整的记录被截断，快照或日志中间损坏时返回 ErrCorrupted，格式版本不一致时返回 ErrVersionMismatch。
+// 配置了 WithSnapshotEncryption 时快照和日志都会加密
+// compactInterval > 0 时后台定期调用 CompactWAL
+func (c *Cache[K, V]) OpenWAL(path string, compactInterval time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.wal != nil {
+		return errors.New("lru: WAL already opened")
+	}
+
+	aead, err := newAEAD(c.snapshotKey)
+	if err != nil {
+		return err
+	}
+	w := &wal{path: path, snapshotPath: path + ".snapshot", aead: aead}
+	if snapshot, err := os.Open(w.snapshotPath); err == nil {
+		records, err := readSnapshot[K, V](bufio.NewReader(snapshot), aead)
+		_ = snapshot.Close()
+		if err != nil {
+			return err
+		}
+		c.applyAllUnlock(records)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	encrypted, err := c.replayWALUnlock(file, aead)
+	if err == nil && encrypted != (aead != nil) {
+		// 加密配置发生了变化，立即压缩，之后的日志按新配置写入
+		if err = c.saveUnlock(w.snapshotPath); err == nil {
+			err = resetWAL(file, aead)
+		}
+	}
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	w.file = file
+
+	if compactInterval > 0 {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go c.pprofDo(context.Background(), "compact_wal", func(context.Context) { c.compactLoop(w, compactInterval) })
+	}
+	c.wal = w
+	return nil
+}
+
+// replayWALUnlock 重放日志并将写位置定位到最后一条完整记录之后，返回日志是否加密
+// 最后一条记录不完整或校验失败视为写入时崩溃，截断丢弃；中间的记录损坏返回 ErrCorrupted
+func (c *Cache[K, V]) replayWALUnlock(file *os.File, aead cipher.AEAD) (bool, error) {
+	r := bufio.NewReader(file)
+	flags, err := readHeader(r, walMagic)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return aead != nil, resetWAL(file, aead)
+	}
+	if err != nil {
+		return false, err
+	}
+	encrypted := flags&flagEncrypted != 0
+	if !encrypted {
+		aead = nil
+	} else if aead == nil {
+		return true, fmt.Errorf("%w: WAL is encrypted but no key configured", ErrDecrypt)
+	}
+
+	records, offset, err := readRecords[K, V](r, aead)
+	if errors.Is(err, ErrCorrupted) {
+		if _, peekErr := r.Peek(1); peekErr == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return encrypted, err
+	}
+	c.applyAllUnlock(records)
+
+	offset += headerSize
+	if err = file.Truncate(offset); err != nil {
+		return encrypted, err
+	}
+	_, err = file.Seek(offset, io.SeekStart)
+	return encrypted, err
+}
+
+// resetWAL 清空日志，只保留文件头
+func resetWAL(file *os.File, aead cipher.AEAD) error {
+	if err := file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return writeHeader(file, walMagic, aead)
+}
+
+func (c *Cache[K, V]) compactLoop(w *wal, interval time.Duration) {
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			_ = c.CompactWAL()
+		}
+	}
+}
+
+// CompactWAL 将当前缓存写为快照并清空日志，期间持有写锁
+func (c *Cache[K, V]) CompactWAL() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.wal == nil {
+		return errors.New("lru: WAL not opened")
+	}
+	if err := c.saveUnlock(c.wal.snapshotPath); err != nil {
+		return err
+	}
+	return resetWAL(c.wal.file, c.wal.aead)
+}
+
+// WALError 返回写日志遇到的第一个错误
+func (c *Cache[K, V]) WALError() error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if c.wal == nil {
+		return nil
+	}
+	return c.wal.err
+}
+
+// CloseWAL 停止后台压缩并关闭日志，返回写日志遇到的第一个错误
+func (c *Cache[K, V]) CloseWAL() error {
+	c.lock.Lock()
+	w := c.wal
+	c.wal = nil
+	c.lock.Unlock()
+	if w == nil {
+		return nil
+	}
+
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+	err := w.file.Close()
+	if w.err != nil {
+		err = w.err
+	}
+	return err
+}
+
+// appendWALUnlock 追加写入一条日志记录，未开启预写日志或已经写入失败时忽略
+func (c *Cache[K, V]) appendWALUnlock(op byte, key K, value V, expireAt time.Time) {
+	if c.wal == nil || c.wal.err != nil {
+		return
+	}
+	c.wal.err = writeRecord(c.wal.file, c.wal.aead, &record[K, V]{Op: op, Key: key, Value: value, ExpireAt: expireAt})
+}
+
+// walHealthUnlock 在健康报告中加入预写日志的状态
+func (c *Cache[K, V]) walHealthUnlock(report *HealthReport) {
+	if c.wal == nil {
+		return
+	}
+	report.WALOpen = true
+	report.WALError = c.wal.err
+	if c.wal.done != nil {
+		select {
+		case <-c.wal.done:
+		default:
+			report.CompactorAlive = true
+		}
+		report.Healthy = report.Healthy && report.CompactorAlive
+	}
+	report.Healthy = report.Healthy && report.WALError == nil
+}
+
+// ReplayWAL 在 base 上按顺序重放预写日志 r，返回结果，用于在缓存之外查看和转换日志
+// base 通常为 OpenWAL 的快照 path + ".snapshot" 中的项。结果按最后一次写入的先后排列，不考虑容量，
+// 日志尾部不完整的记录被忽略。key 为 WithSnapshotEncryption 的密钥，日志未加密时忽略
+func ReplayWAL[K comparable, V interface{}](r io.Reader, key []byte, base []SnapshotEntry[K, V]) ([]SnapshotEntry[K, V], error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(r)
+	flags, err := readHeader(br, walMagic)
+	if err != nil {
+		return nil, err
+	}
+	if flags&flagEncrypted == 0 {
+		aead = nil
+	} else if aead == nil {
+		return nil, fmt.Errorf("%w: WAL is encrypted but no key configured", ErrDecrypt)
+	}
+	records, _, err := readRecords[K, V](br, aead)
+	if errors.Is(err, ErrCorrupted) {
+		if _, peekErr := br.Peek(1); peekErr == io.EOF {
+			err = nil
+		}
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	li := list.New() // list<SnapshotEntry>，从旧到新
+	m := map[K]*list.Element{}
+	put := func(e SnapshotEntry[K, V]) {
+		if ele, ok := m[e.Key]; ok {
+			li.Remove(ele)
+		}
+		m[e.Key] = li.PushBack(e)
+	}
+	for _, e := range base {
+		put(e)
+	}
+	for _, rec := range records {
+		switch rec.Op {
+		case opPut:
+			put(SnapshotEntry[K, V]{Key: rec.Key, Value: rec.Value, ExpireAt: rec.ExpireAt, Stale: rec.Stale})
+		case opInvalidate:
+			if ele, ok := m[rec.Key]; ok {
+				e := ele.Value.(SnapshotEntry[K, V])
+				e.Stale = true
+				ele.Value = e
+			}
+		case opRemove:
+			if ele, ok := m[rec.Key]; ok {
+				li.Remove(ele)
+				delete(m, rec.Key)
+			}
+		case opClear:
+			li.Init()
+			m = map[K]*list.Element{}
+		}
+	}
+	entries := make([]SnapshotEntry[K, V], 0, li.Len())
+	for ele := li.Front(); ele != nil; ele = ele.Next() {
+		entries = append(entries, ele.Value.(SnapshotEntry[K, V]))
+	}
+	return entries, nil
+}
+
+// WriteWAL 写出可被 OpenWAL 重放的预写日志，每一项为一条写入记录，entries 按最久未访问到最近访问的顺序排列
+// key 不为空时加密
+func WriteWAL[K comparable, V interface{}](w io.Writer, key []byte, entries []SnapshotEntry[K, V]) error {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return err
+	}
+	return writeEntries(w, walMagic, aead, entries)
+}
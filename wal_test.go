@@ -0,0 +1,219 @@
+//go:build !lru_minimal
+
+package lru
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCache_OpenWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[int, int](10, nil, nil)
+	if err := cache.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	for i := 0; i < 5; i++ {
+		cache.Put(i, i*10)
+	}
+	cache.Remove(1)
+	cache.RemoveIf(func(k int) bool { return k == 3 })
+	if err := cache.CloseWAL(); err != nil {
+		panic(err)
+	}
+
+	recovered := New[int, int](10, nil, nil)
+	if err := recovered.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	defer recovered.CloseWAL()
+	if !reflect.DeepEqual(recovered.AllKeys(), []int{4, 2, 0}) {
+		panic(recovered.AllKeys())
+	}
+	value, _ := recovered.Get(4)
+	if value != 40 {
+		panic(value)
+	}
+}
+
+func TestCache_OpenWAL_Evict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[int, int](2, nil, nil)
+	if err := cache.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Get(1)
+	cache.Put(3, 3) // 淘汰 2
+	if !reflect.DeepEqual(cache.AllKeys(), []int{3, 1}) {
+		panic(cache.AllKeys())
+	}
+	if err := cache.CloseWAL(); err != nil {
+		panic(err)
+	}
+
+	recovered := New[int, int](2, nil, nil)
+	if err := recovered.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	defer recovered.CloseWAL()
+	if !reflect.DeepEqual(recovered.AllKeys(), []int{3, 1}) {
+		panic(recovered.AllKeys())
+	}
+}
+
+func TestCache_CompactWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[int, int](10, nil, nil)
+	if err := cache.OpenWAL(path, time.Millisecond); err != nil {
+		panic(err)
+	}
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	time.Sleep(20 * time.Millisecond)
+	cache.RemoveAll()
+	cache.Put(3, 3)
+	if err := cache.CloseWAL(); err != nil {
+		panic(err)
+	}
+	if _, err := os.Stat(path + ".snapshot"); err != nil {
+		panic(err)
+	}
+
+	// 模拟写入最后一条记录时崩溃
+	info, _ := os.Stat(path)
+	if err := os.Truncate(path, info.Size()-1); err != nil {
+		panic(err)
+	}
+	recovered := New[int, int](10, nil, nil)
+	if err := recovered.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	defer recovered.CloseWAL()
+	if recovered.Number() != 0 {
+		panic(recovered.AllKeys())
+	}
+	recovered.Put(4, 4)
+	if !reflect.DeepEqual(recovered.AllKeys(), []int{4}) {
+		panic(recovered.AllKeys())
+	}
+}
+
+func TestCache_OpenWAL_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[int, int](10, nil, nil)
+	if err := cache.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	if err := cache.CloseWAL(); err != nil {
+		panic(err)
+	}
+	data, _ := os.ReadFile(path)
+
+	// 最后一条记录损坏，视为崩溃时写了一半
+	broken := append([]byte(nil), data...)
+	broken[len(broken)-1] ^= 0xff
+	_ = os.WriteFile(path, broken, 0o644)
+	recovered := New[int, int](10, nil, nil)
+	if err := recovered.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	_ = recovered.CloseWAL()
+	if !reflect.DeepEqual(recovered.AllKeys(), []int{1}) {
+		panic(recovered.AllKeys())
+	}
+
+	// 中间的记录损坏
+	broken = append([]byte(nil), data...)
+	broken[headerSize+recordHeaderSize] ^= 0xff
+	_ = os.WriteFile(path, broken, 0o644)
+	if err := New[int, int](10, nil, nil).OpenWAL(path, 0); !errors.Is(err, ErrCorrupted) {
+		panic(err)
+	}
+}
+
+func TestCache_Health_WAL(t *testing.T) {
+	cache := New[int, int](10, nil, nil)
+	report := cache.Health()
+	if report.WALOpen {
+		panic(report)
+	}
+
+	path := filepath.Join(t.TempDir(), "wal")
+	if err := cache.OpenWAL(path, 1<<30); err != nil {
+		panic(err)
+	}
+	if err := cache.CompactWAL(); err != nil {
+		panic(err)
+	}
+	report = cache.Health()
+	if !report.Healthy || !report.WALOpen || !report.CompactorAlive || report.LastSnapshot.IsZero() {
+		panic(report)
+	}
+	if err := cache.CloseWAL(); err != nil {
+		panic(err)
+	}
+}
+
+func TestCache_Invalidate_WAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[int, int](10, nil, nil)
+	if err := cache.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	cache.Put(1, 1)
+	cache.Put(2, 2)
+	cache.Invalidate(1)
+	if err := cache.CompactWAL(); err != nil {
+		panic(err)
+	}
+	cache.Invalidate(2)
+	_ = cache.CloseWAL()
+
+	recovered := New[int, int](10, nil, nil)
+	if err := recovered.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	defer recovered.CloseWAL()
+	_, stale1, _ := recovered.GetStale(1)
+	_, stale2, _ := recovered.GetStale(2)
+	if !stale1 || !stale2 {
+		panic("stale flag lost")
+	}
+}
+
+func TestReplayWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+	cache := New[string, int](10, nil, nil)
+	if err := cache.OpenWAL(path, 0); err != nil {
+		panic(err)
+	}
+	cache.Put("b", 2)
+	cache.Put("c", 3)
+	cache.Invalidate("b")
+	cache.Remove("c")
+	cache.Put("a", 11)
+	_ = cache.CloseWAL()
+
+	file, err := os.Open(path)
+	if err != nil {
+		panic(err)
+	}
+	defer file.Close()
+	base := []SnapshotEntry[string, int]{{Key: "a", Value: 1}, {Key: "z", Value: 26}}
+	entries, err := ReplayWAL(file, nil, base)
+	if err != nil {
+		panic(err)
+	}
+	want := []SnapshotEntry[string, int]{{Key: "z", Value: 26}, {Key: "b", Value: 2, Stale: true}, {Key: "a", Value: 11}}
+	if !reflect.DeepEqual(entries, want) {
+		panic(entries)
+	}
+}
@@ -0,0 +1,77 @@
+package lru
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// warmBatch WarmFromChannel 每次加锁最多写入的项数
+const warmBatch = 256
+
+// WarmFromChannel 用 workers 个 goroutine 从 ch 读取缓存项并写入，直到 ch 关闭后返回写入和被拒绝的项数
+// 每个 goroutine 把 ch 中已就绪的项攒成一批，一次加锁写入，适合从数据库游标等流式来源预热缓存。
+// 每一项与 PutWithTTL 一样写入：没有过期时间的项使用 WithExpiry 的过期时间，别名写入 primary。
+// progress 不为空时每写入一批调用一次，参数为累计写入的项数，可能被多个 goroutine 并发调用。
+// 已过期的项、SizeStrict 下大小不合法的项和未通过 WithKeyValidator 校验的项被拒绝
+func (c *Cache[K, V]) WarmFromChannel(ch <-chan Entry[K, V], workers int, progress func(loaded int)) (loaded, rejected int) {
+	if workers <= 0 {
+		workers = 1
+	}
+	var loadedN, rejectedN atomic.Int64
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go c.pprofDo(context.Background(), "warm", func(context.Context) {
+			defer wg.Done()
+			batch := make([]Entry[K, V], 0, warmBatch)
+			for {
+				e, ok := <-ch
+				if !ok {
+					return
+				}
+				batch = append(batch[:0], e)
+			fill:
+				for len(batch) < warmBatch {
+					select {
+					case e, ok = <-ch:
+						if !ok {
+							break fill
+						}
+						batch = append(batch, e)
+					default:
+						break fill
+					}
+				}
+				put, bad := c.putBatch(batch)
+				rejectedN.Add(int64(bad))
+				n := loadedN.Add(int64(put))
+				if progress != nil {
+					progress(int(n))
+				}
+				if !ok {
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	return int(loadedN.Load()), int(rejectedN.Load())
+}
+
+// putBatch 加锁写入一批缓存项，返回写入和被拒绝的项数
+func (c *Cache[K, V]) putBatch(batch []Entry[K, V]) (loaded, rejected int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	for i := range batch {
+		e := &batch[i]
+		if e.expired(now) || c.tryPutAtUnlock(e.key, e.value, 0, e.expireAt) != nil {
+			rejected++
+			continue
+		}
+		loaded++
+	}
+	return loaded, rejected
+}
@@ -0,0 +1,75 @@
+package lru
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCache_WarmFromChannel(t *testing.T) {
+	cache := New[int, int](10000, nil, nil)
+	ch := make(chan Entry[int, int], 100)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			ch <- NewEntry(i, i)
+		}
+		ch <- NewEntryWithTTL(-1, -1, time.Nanosecond) // 写入前已过期
+		close(ch)
+	}()
+
+	var last atomic.Int64
+	loaded, rejected := cache.WarmFromChannel(ch, 4, func(loaded int) {
+		for {
+			old := last.Load()
+			if int64(loaded) <= old || last.CompareAndSwap(old, int64(loaded)) {
+				return
+			}
+		}
+	})
+	if loaded != 1000 || rejected != 1 || cache.Number() != 1000 || last.Load() != 1000 {
+		panic(loaded)
+	}
+	if value, _ := cache.Get(500); value != 500 {
+		panic(value)
+	}
+}
+
+func TestCache_WarmFromChannel_Expiry(t *testing.T) {
+	cache := New[string, int](10, nil, func(key string, value int) int { return value },
+		WithExpiry(func(key string, value int) time.Duration { return time.Hour }),
+		WithSizePolicy[string, int](SizeStrict))
+	ch := make(chan Entry[string, int], 3)
+	ch <- NewEntry("a", 1)
+	ch <- NewEntryWithTTL("b", 2, time.Minute)
+	ch <- NewEntry("bad", -1) // SizeStrict 下大小不合法
+	close(ch)
+	loaded, rejected := cache.WarmFromChannel(ch, 1, nil)
+	if loaded != 2 || rejected != 1 {
+		panic(loaded)
+	}
+	// 没有过期时间的项使用 WithExpiry 的默认值
+	if _, _, expireAt, ok := cache.GetWithExpiration("a"); !ok || expireAt.IsZero() {
+		panic(expireAt)
+	}
+	if _, _, expireAt, _ := cache.GetWithExpiration("b"); time.Until(expireAt) > time.Minute {
+		panic(expireAt)
+	}
+}
+
+func TestCache_WarmFromChannel_Alias(t *testing.T) {
+	cache := New[string, int](10, nil, nil)
+	cache.Put("p", 1)
+	cache.Alias("a", "p")
+	ch := make(chan Entry[string, int], 1)
+	ch <- NewEntry("a", 2)
+	close(ch)
+	if loaded, _ := cache.WarmFromChannel(ch, 1, nil); loaded != 1 {
+		panic(loaded)
+	}
+	if keys := cache.AllKeys(); len(keys) != 1 || keys[0] != "p" {
+		panic(keys)
+	}
+	if value, _ := cache.Get("p"); value != 2 {
+		panic(value)
+	}
+}